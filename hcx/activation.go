@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -35,7 +36,7 @@ type ActivateDataItemConfig struct {
 
 // PostActivate sends a request to activate a configuration using the provided body and returns the resulting
 // ActivateBody object. Returns an error if the request fails or the response cannot be parsed.
-func PostActivate(c *Client, body ActivateBody) (ActivateBody, error) {
+func PostActivate(ctx context.Context, c *Client, body ActivateBody) (ActivateBody, error) {
 	resp := ActivateBody{}
 
 	var buf bytes.Buffer
@@ -44,7 +45,7 @@ func PostActivate(c *Client, body ActivateBody) (ActivateBody, error) {
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s:9443/api/admin/global/config/hcx", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s:9443/api/admin/global/config/hcx", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -64,10 +65,10 @@ func PostActivate(c *Client, body ActivateBody) (ActivateBody, error) {
 
 // GetActivate sends a request to retrieve the current activation configuration and returns the resulting ActivateBody
 // object. Returns an error if the request fails or the response cannot be parsed.
-func GetActivate(c *Client) (ActivateBody, error) {
+func GetActivate(ctx context.Context, c *Client) (ActivateBody, error) {
 	resp := ActivateBody{}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s:9443/api/admin/global/config/hcx", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s:9443/api/admin/global/config/hcx", c.HostURL), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -87,7 +88,7 @@ func GetActivate(c *Client) (ActivateBody, error) {
 
 // DeleteActivate sends a request to remove the activation configuration using the provided body and returns the
 // resulting ActivateBody object. Returns an error if the request fails or the response cannot be parsed.
-func DeleteActivate(c *Client, body ActivateBody) (ActivateBody, error) {
+func DeleteActivate(ctx context.Context, c *Client, body ActivateBody) (ActivateBody, error) {
 	resp := ActivateBody{}
 
 	var buf bytes.Buffer
@@ -96,7 +97,7 @@ func DeleteActivate(c *Client, body ActivateBody) (ActivateBody, error) {
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s:9443/api/admin/global/config/hcx", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s:9443/api/admin/global/config/hcx", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create DELETE request: %w", err)
 	}
@@ -0,0 +1,75 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// adoptSchema returns the schema of the attribute that enables the adoption of an existing HCX object on create. The
+// lookup describes how the existing object is found.
+func adoptSchema(lookup string) *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeBool,
+		Description: fmt.Sprintf("Adopt an existing object with the same %s instead of creating a new one, if it matches "+
+			"the configuration. Defaults to the adopt_existing setting of the provider.", lookup),
+		Optional: true,
+	}
+}
+
+// adoptExisting reports whether create should adopt an existing object. The adopt_existing attribute of the resource
+// takes precedence over the setting of the provider when it is set.
+func adoptExisting(d *schema.ResourceData, c *Client) bool {
+	if v := d.GetRawConfig().GetAttr("adopt_existing"); !v.IsNull() && v.IsKnown() {
+		return v.True()
+	}
+
+	return c.AdoptExisting
+}
+
+// attrConfigured reports whether key is set in the configuration, so that an unset optional and computed attribute is
+// not compared with the existing object.
+func attrConfigured(d *schema.ResourceData, key string) bool {
+	v := d.GetRawConfig().GetAttr(key)
+	return v.IsKnown() && !v.IsNull()
+}
+
+// adoption collects the differences between the configuration and an existing HCX object considered for adoption.
+type adoption struct {
+	kind       string
+	name       string
+	mismatches []string
+}
+
+// compare records a mismatch when the configured value of attr differs from the value of the existing object.
+func (a *adoption) compare(attr string, configured, existing interface{}) {
+	if configured != existing {
+		a.mismatches = append(a.mismatches, fmt.Sprintf("%s is %v in HCX but %v in the configuration", attr, existing, configured))
+	}
+}
+
+// compareSet records a mismatch when the configured values of attr differ from the values of the existing object,
+// regardless of their order.
+func (a *adoption) compareSet(attr string, configured, existing []string) {
+	configured = slices.Sorted(slices.Values(configured))
+	existing = slices.Sorted(slices.Values(existing))
+	a.compare(attr, strings.Join(configured, ", "), strings.Join(existing, ", "))
+}
+
+// err returns an error describing the mismatches, or nil if the existing object matches the configuration.
+func (a *adoption) err() error {
+	if len(a.mismatches) == 0 {
+		log.Printf("[INFO] Adopting existing %s %q", a.kind, a.name)
+		return nil
+	}
+
+	return fmt.Errorf("existing %s %q does not match the configuration and cannot be adopted: %s", a.kind, a.name,
+		strings.Join(a.mismatches, "; "))
+}
@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -24,7 +25,7 @@ type InsertCertificateResult struct {
 
 // InsertCertificate sends a request to create a new certificate using the provided body and returns an
 // InsertCertificateResult object. Returns an error if the request fails or the response cannot be parsed.
-func InsertCertificate(c *Client, body InsertCertificateBody) (InsertCertificateResult, error) {
+func InsertCertificate(ctx context.Context, c *Client, body InsertCertificateBody) (InsertCertificateResult, error) {
 
 	resp := InsertCertificateResult{}
 
@@ -34,7 +35,7 @@ func InsertCertificate(c *Client, body InsertCertificateBody) (InsertCertificate
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/admin/certificates", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/admin/certificates", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
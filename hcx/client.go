@@ -5,14 +5,15 @@
 package hcx
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,7 +21,7 @@ import (
 type Client struct {
 	HostURL            string
 	HTTPClient         *http.Client
-	Token              string
+	VmcToken           string
 	HcxToken           string
 	AdminUsername      string
 	AdminPassword      string
@@ -28,6 +29,20 @@ type Client struct {
 	Password           string
 	IsAuthenticated    bool
 	AllowUnverifiedSSL bool
+	Retry              RetryPolicy
+
+	// AdoptExisting adopts existing HCX objects matching the configuration on create, unless a resource overrides it.
+	AdoptExisting bool
+
+	adminHTTPClient *http.Client
+	cloudHTTPClient *http.Client
+
+	// authMu serializes logins so that concurrent requests share a single in-flight authentication and never race on
+	// token and IsAuthenticated.
+	authMu sync.Mutex
+
+	// token is the HCX session token, only accessed under authMu.
+	token string
 }
 
 // AuthStruct represents a structure containing username and password for authentication purposes.
@@ -55,7 +70,22 @@ type Entries struct {
 
 // HcxConnectorAuthenticate authenticates the client with the HCX service by sending a request with user credentials.
 // It retrieves and stores the HCX authorization token required for subsequent requests.
-func (c *Client) HcxConnectorAuthenticate() error {
+func (c *Client) HcxConnectorAuthenticate(ctx context.Context) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.IsAuthenticated = false
+	if err := c.login(ctx); err != nil {
+		return err
+	}
+	c.IsAuthenticated = true
+
+	return nil
+}
+
+// login sends the user credentials to the HCX service and stores the returned session token. It must be called with
+// authMu held.
+func (c *Client) login(ctx context.Context) error {
 
 	rb, err := json.Marshal(AuthStruct{
 		Username: c.Username,
@@ -65,75 +95,81 @@ func (c *Client) HcxConnectorAuthenticate() error {
 		return fmt.Errorf("failed to marshal authentication request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/sessions", c.HostURL), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/sessions", c.HostURL), strings.NewReader(string(rb)))
 	if err != nil {
 		return fmt.Errorf("failed to create authentication request: %w", err)
 	}
 
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: c.AllowUnverifiedSSL, // #nosec G402
-	}
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = tlsConfig
+	// Logging in has no side effects, so the request is retried like any idempotent request.
+	req = withIdempotent(req)
 
 	var resp *http.Response
 	for {
-		resp, err = c.HTTPClient.Do(req)
-		if err != nil {
-			time.Sleep(180 * time.Second)
-			resp, err = c.HTTPClient.Do(req)
-
-			if err != nil {
-				return fmt.Errorf("authentication failed after retry; check credentials: %w", err)
-			}
-		}
-
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
+		var body []byte
+		resp, body, err = c.send(c.HTTPClient, req)
 		if err != nil {
-			return fmt.Errorf("failed to read authentication response body: %w", err)
+			return fmt.Errorf("authentication failed; check connectivity and credentials: %w", err)
 		}
 
 		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted {
 			break
 		}
 
-		// Check if SSO is ready.
-		var xmlmessage Entries
-		err = xml.Unmarshal(body, &xmlmessage)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshal XML response: %w", err)
+		// Wait only while SSO is not ready; any other error, including other certificate errors, fails fast.
+		hcxErr := newHCXError(req, resp.StatusCode, body)
+		if !hcxErr.IsSSONotReady() {
+			return fmt.Errorf("authentication failed: %w", hcxErr)
 		}
+		log.Printf("[INFO] HCX SSO is not ready yet: %s", hcxErr.Message)
 
-		certificatePb := false
-		for _, j := range xmlmessage.Entry {
-			if j.Strings[0] == "message" {
-				if j.Strings[1] == "'Trusted root certificates' value should not be empty" {
-					certificatePb = true
-					log.Println("Certificate error")
-				}
-			}
+		if err := sleepContext(ctx, 10*time.Second); err != nil {
+			return err
 		}
 
-		if !certificatePb {
-			return fmt.Errorf("unexpected authentication response body: %s", body)
+		req, err = cloneRequest(req)
+		if err != nil {
+			return err
 		}
-
-		time.Sleep(10 * time.Second)
 	}
 
 	// Parse response header.
-	c.Token = resp.Header.Get("x-hm-authorization")
+	c.token = resp.Header.Get("x-hm-authorization")
 
 	return nil
 }
 
 // NewClient initializes and returns a new Client instance with the provided configuration, including authentication
-// details, HCX URL, and SSL settings.
-func NewClient(hcx, username *string, password *string, adminUsername *string, adminPassword *string, allowUnverifiedSSL *bool, vmcToken *string) (*Client, error) {
+// details, HCX URL, and SSL settings. Each client owns a dedicated HTTP transport built from the transport
+// configuration, so settings never leak between provider instances or into http.DefaultTransport.
+func NewClient(hcx, username *string, password *string, adminUsername *string, adminPassword *string, allowUnverifiedSSL *bool, vmcToken *string, transportConfig TransportConfig) (*Client, error) {
+	transportConfig.AllowUnverifiedSSL = *allowUnverifiedSSL
+
+	transport, err := newTransport(transportConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HCX transport: %w", err)
+	}
+
+	// VMware Cloud Services endpoints are public and always verified against the system trust store.
+	cloudTransport, err := newTransport(TransportConfig{
+		ProxyURL: transportConfig.ProxyURL,
+		NoProxy:  transportConfig.NoProxy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure VMware Cloud transport: %w", err)
+	}
+
 	c := Client{
 		HTTPClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Transport: transport,
+			Timeout:   60 * time.Second,
+		},
+		adminHTTPClient: &http.Client{
+			Transport: transport,
+			Timeout:   300 * time.Second,
+		},
+		cloudHTTPClient: &http.Client{
+			Transport: cloudTransport,
+			Timeout:   60 * time.Second,
 		},
 		HostURL:            *hcx,
 		Username:           *username,
@@ -142,34 +178,148 @@ func NewClient(hcx, username *string, password *string, adminUsername *string, a
 		AdminPassword:      *adminPassword,
 		IsAuthenticated:    false,
 		AllowUnverifiedSSL: *allowUnverifiedSSL,
-		Token:              *vmcToken,
+		VmcToken:           *vmcToken,
+		Retry:              DefaultRetryPolicy(),
 	}
 
 	return &c, nil
 }
 
+// cloudClient returns a Client for the given VMware Cloud Services or HCX Cloud URL that shares the cloud HTTP client
+// of the provider client.
+func (c *Client) cloudClient(hostURL string) *Client {
+	return &Client{
+		HTTPClient: c.cloudHTTPClient,
+		HostURL:    hostURL,
+		Retry:      c.Retry,
+	}
+}
+
 // doRequest performs an authenticated HTTP request. If the client is not yet authenticated, it performs authentication
-// first, then executes the request. Returns the HTTP response, response body, and any encountered error.
+// first, then executes the request. If the session has expired or was invalidated, it authenticates again and retries
+// the request once. Returns the HTTP response, response body, and any encountered error.
 func (c *Client) doRequest(req *http.Request) (*http.Response, []byte, error) {
 
-	if !c.IsAuthenticated {
-		err := c.HcxConnectorAuthenticate()
+	token, err := c.authenticate(req.Context())
+	if err != nil {
+		return nil, nil, fmt.Errorf("authentication failed during request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-hm-authorization", token)
+
+	res, body, err := c.send(c.HTTPClient, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		log.Printf("[DEBUG] HCX session rejected with status %d, authenticating again", res.StatusCode)
+
+		token, err = c.reauthenticate(req.Context(), token)
 		if err != nil {
-			return nil, nil, fmt.Errorf("authentication failed during request: %w", err)
+			return nil, nil, fmt.Errorf("re-authentication failed during request: %w", err)
+		}
+
+		retry, err := cloneRequest(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		retry.Header.Set("x-hm-authorization", token)
+
+		res, body, err = c.send(c.HTTPClient, retry)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
+		return nil, nil, newHCXError(req, res.StatusCode, body)
+	}
+
+	return res, body, nil
+}
+
+// authenticate returns the current session token, logging in first if the client is not yet authenticated. Concurrent
+// callers wait for a single in-flight login.
+func (c *Client) authenticate(ctx context.Context) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if !c.IsAuthenticated {
+		if err := c.login(ctx); err != nil {
+			return "", err
 		}
 		c.IsAuthenticated = true
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-hm-authorization", c.Token)
+	return c.token, nil
+}
+
+// reauthenticate logs in again after the server rejected staleToken. If another request already replaced the stale
+// token while this one was waiting for the lock, the new token is returned without logging in again.
+func (c *Client) reauthenticate(ctx context.Context, staleToken string) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.IsAuthenticated && c.token != staleToken {
+		return c.token, nil
+	}
+
+	c.IsAuthenticated = false
+	if err := c.login(ctx); err != nil {
+		return "", err
+	}
+	c.IsAuthenticated = true
+
+	return c.token, nil
+}
+
+// send executes the request with the provided HTTP client and reads the whole response body. Transient failures are
+// retried according to the retry policy of the client. Returns the HTTP response, response body, and any encountered
+// error.
+func (c *Client) send(httpClient *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	retryable := isIdempotent(req) || c.Retry.RetryNonIdempotent
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 {
+			var err error
+			if r, err = cloneRequest(req); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		res, body, err := sendOnce(httpClient, r)
+		if !retryable || attempt >= c.Retry.MaxRetries || !c.Retry.retryable(req.Context(), res, err) {
+			return res, body, err
+		}
+
+		wait := c.Retry.backoff(attempt, res)
+		if err != nil {
+			log.Printf("[DEBUG] %s %s failed: %s; retrying in %s (attempt %d of %d)", req.Method, logURL(req.URL), err, wait, attempt+1, c.Retry.MaxRetries)
+		} else {
+			log.Printf("[DEBUG] %s %s returned status %d; retrying in %s (attempt %d of %d)", req.Method, logURL(req.URL), res.StatusCode, wait, attempt+1, c.Retry.MaxRetries)
+		}
 
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: c.AllowUnverifiedSSL, // #nosec G402
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, nil, err
+		}
 	}
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = tlsConfig
+}
 
-	res, err := c.HTTPClient.Do(req)
+// logURL returns the URL of a request for logs and errors, without its user information and query string, which may
+// hold credentials.
+func logURL(u *url.URL) string {
+	redacted := *u
+	redacted.RawQuery = ""
+	return redacted.Redacted()
+}
+
+// sendOnce executes the request a single time and reads the whole response body.
+func sendOnce(httpClient *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to send HTTP request: %w", err)
 	}
@@ -180,11 +330,26 @@ func (c *Client) doRequest(req *http.Request) (*http.Response, []byte, error) {
 		return nil, nil, fmt.Errorf("failed to read HTTP response: %w", err)
 	}
 
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
-		return nil, nil, fmt.Errorf("unexpected response status: %d, body: %s", res.StatusCode, body)
+	return res, body, nil
+}
+
+// cloneRequest returns a copy of the request with a fresh body, so that it can be sent again.
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, fmt.Errorf("cannot resend %s request to %s: request body is not replayable", req.Method, logURL(req.URL))
+		}
+
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy request body: %w", err)
+		}
+		clone.Body = body
 	}
 
-	return res, body, nil
+	return clone, nil
 }
 
 // doAdminRequest executes an HTTP request using the admin credentials for Basic Authentication. It supports requests
@@ -195,32 +360,19 @@ func (c *Client) doAdminRequest(req *http.Request) (*http.Response, []byte, erro
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
-	c.HTTPClient.Timeout = 300 * time.Second
-
 	if (c.AdminUsername == "") || (c.AdminPassword == "") {
 		return nil, nil, fmt.Errorf("admin_username or admin_password is empty")
 	}
 
 	req.SetBasicAuth(c.AdminUsername, c.AdminPassword)
 
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: c.AllowUnverifiedSSL, // #nosec G402
-	}
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = tlsConfig
-
-	res, err := c.HTTPClient.Do(req)
+	res, body, err := c.send(c.adminHTTPClient, req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to send HTTP request: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read HTTP response: %w", err)
+		return nil, nil, err
 	}
 
 	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusAccepted {
-		return nil, nil, fmt.Errorf("unexpected response status from admin request: %d, body: %s", res.StatusCode, body)
+		return nil, nil, newHCXError(req, res.StatusCode, body)
 	}
 
 	return res, body, nil
@@ -231,31 +383,36 @@ func (c *Client) doAdminRequest(req *http.Request) (*http.Response, []byte, erro
 func (c *Client) doVmcRequest(req *http.Request) (*http.Response, []byte, error) {
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	if c.HcxToken != "" {
 		req.Header.Set("x-hm-authorization", c.HcxToken)
 	}
 
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: c.AllowUnverifiedSSL, // #nosec G402
-	}
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = tlsConfig
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to send HTTP request: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
+	res, body, err := c.send(c.HTTPClient, req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read HTTP response: %w", err)
+		return nil, nil, err
 	}
 
 	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
-		return nil, nil, fmt.Errorf("unexpected vmc response status: %d, body: %s", res.StatusCode, body)
+		return nil, nil, newHCXError(req, res.StatusCode, body)
 	}
 
 	return res, body, nil
 }
+
+// sleepContext pauses for the given duration or until the context is cancelled, whichever comes first. Returns the
+// context error if the context is cancelled before the duration elapses.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -109,7 +110,7 @@ type GetComputeProfileResultItem struct {
 
 // InsertComputeProfile sends a request to create a new compute profile using the provided body and returns an
 // InsertComputeProfileResult object. Returns an error if the request fails or the response cannot be parsed.
-func InsertComputeProfile(c *Client, body InsertComputeProfileBody) (InsertComputeProfileResult, error) {
+func InsertComputeProfile(ctx context.Context, c *Client, body InsertComputeProfileBody) (InsertComputeProfileResult, error) {
 
 	resp := InsertComputeProfileResult{}
 
@@ -119,7 +120,7 @@ func InsertComputeProfile(c *Client, body InsertComputeProfileBody) (InsertCompu
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/interconnect/computeProfiles", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/interconnect/computeProfiles", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -137,14 +138,47 @@ func InsertComputeProfile(c *Client, body InsertComputeProfileBody) (InsertCompu
 	return resp, nil
 }
 
+// UpdateComputeProfile sends a request to edit the compute profile identified by computeProfileID using the provided
+// body and returns an InsertComputeProfileResult object. Returns an error if the request fails or the response cannot
+// be parsed.
+func UpdateComputeProfile(ctx context.Context, c *Client, computeProfileID string, body InsertComputeProfileBody) (InsertComputeProfileResult, error) {
+
+	resp := InsertComputeProfileResult{}
+
+	body.ComputeProfileID = computeProfileID
+
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/hybridity/api/interconnect/computeProfiles/%s", c.HostURL, computeProfileID), &buf)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create PUT request: %w", err)
+	}
+
+	_, r, err := c.doRequest(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send PUT request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to unmarshal PUT response: %w", err)
+	}
+
+	return resp, nil
+}
+
 // DeleteComputeProfile sends a request to delete a specific compute profile identified by computeProfileID and an
 // InsertComputeProfileResult object indicating the result of the operation. Returns an error if the request fails or
 // the response cannot be parsed.
-func DeleteComputeProfile(c *Client, computeProfileID string) (InsertComputeProfileResult, error) {
+func DeleteComputeProfile(ctx context.Context, c *Client, computeProfileID string) (InsertComputeProfileResult, error) {
 
 	resp := InsertComputeProfileResult{}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/hybridity/api/interconnect/computeProfiles/%s", c.HostURL, computeProfileID), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/hybridity/api/interconnect/computeProfiles/%s", c.HostURL, computeProfileID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create DELETE request: %w", err)
 	}
@@ -165,30 +199,70 @@ func DeleteComputeProfile(c *Client, computeProfileID string) (InsertComputeProf
 // GetComputeProfile retrieves the details of a compute profile using the provided endpointID and computeProfileName,
 // returning a GetComputeProfileResultItem object for the matching profile. Returns an error if the request fails, the
 // response cannot be parsed, or no matching profile is found.
-func GetComputeProfile(c *Client, endpointID string, computeProfileName string) (GetComputeProfileResultItem, error) {
+func GetComputeProfile(ctx context.Context, c *Client, endpointID string, computeProfileName string) (GetComputeProfileResultItem, error) {
+
+	items, err := GetComputeProfiles(ctx, c, endpointID)
+	if err != nil {
+		return GetComputeProfileResultItem{}, err
+	}
+
+	for _, j := range items {
+		if j.Name == computeProfileName {
+			return j, nil
+		}
+	}
+
+	return GetComputeProfileResultItem{}, fmt.Errorf("compute profile %s: %w", computeProfileName, ErrNotFound)
+}
+
+// GetComputeProfiles retrieves the compute profiles of the endpoint identified by endpointID. Returns an error if the
+// request fails or the response cannot be parsed.
+func GetComputeProfiles(ctx context.Context, c *Client, endpointID string) ([]GetComputeProfileResultItem, error) {
 
 	resp := GetComputeProfileResult{}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/hybridity/api/interconnect/computeProfiles?endpointId=%s", c.HostURL, endpointID), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/hybridity/api/interconnect/computeProfiles?endpointId=%s", c.HostURL, endpointID), nil)
 	if err != nil {
-		return GetComputeProfileResultItem{}, fmt.Errorf("failed to create GET request: %w", err)
+		return nil, fmt.Errorf("failed to create GET request: %w", err)
 	}
 
 	_, r, err := c.doRequest(req)
 	if err != nil {
-		return GetComputeProfileResultItem{}, fmt.Errorf("failed to send GET request: %w", err)
+		return nil, fmt.Errorf("failed to send GET request: %w", err)
 	}
 
 	err = json.Unmarshal(r, &resp)
 	if err != nil {
-		return GetComputeProfileResultItem{}, fmt.Errorf("failed to unmarshal GET response: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal GET response: %w", err)
 	}
 
-	for _, j := range resp.Items {
-		if j.Name == computeProfileName {
-			return j, nil
-		}
+	return resp.Items, nil
+}
+
+// GetComputeProfileByID retrieves the compute profile identified by computeProfileID. Returns an error wrapping
+// ErrNotFound if the compute profile does not exist.
+func GetComputeProfileByID(ctx context.Context, c *Client, computeProfileID string) (GetComputeProfileResultItem, error) {
+
+	resp := GetComputeProfileResultItem{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/hybridity/api/interconnect/computeProfiles/%s", c.HostURL, computeProfileID), nil)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create GET request: %w", err)
 	}
 
-	return GetComputeProfileResultItem{}, fmt.Errorf("cannot find compute profile: %s", computeProfileName)
+	_, r, err := c.doRequest(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send GET request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to unmarshal GET response: %w", err)
+	}
+
+	if resp.ComputeProfileID == "" {
+		return resp, fmt.Errorf("compute profile %s: %w", computeProfileID, ErrNotFound)
+	}
+
+	return resp, nil
 }
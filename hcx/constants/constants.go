@@ -30,14 +30,20 @@ const (
 	VmcDeactivationFailedStatus   = "DEACTIVATION_FAILED"
 
 	// Status
-	StoppedStatus  = "STOPPED"
-	RunningStatus  = "RUNNING"
-	FailedStatus   = "FAILED"
-	SuccessStatus  = "SUCCESS"
-	RealizedStatus = "REALIZED"
+	StoppedStatus     = "STOPPED"
+	RunningStatus     = "RUNNING"
+	FailedStatus      = "FAILED"
+	SuccessStatus     = "SUCCESS"
+	RealizedStatus    = "REALIZED"
+	CancelledStatus   = "CANCELLED"
+	RolledBackStatus  = "ROLLED_BACK"
+	TimedOutStatus    = "TIMED_OUT"
+	InterruptedStatus = "INTERRUPTED"
 
 	// Network Profile
 	DefaultNetworkProfileOrg = "DEFAULT"
+	NetworkProfileMinMTU     = 1150
+	NetworkProfileMaxMTU     = 9000
 
 	// Location
 	DefaultLatitude  = 0
@@ -37,14 +37,14 @@ func dataSourceComputeProfileRead(ctx context.Context, d *schema.ResourceData, m
 
 	client := m.(*Client)
 
-	res, err := GetLocalCloudList(client)
+	res, err := GetLocalCloudList(ctx, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	network := d.Get("name").(string)
 
-	cp, err := GetComputeProfile(client, res.Data.Items[0].EndpointID, network)
+	cp, err := GetComputeProfile(ctx, client, res.Data.Items[0].EndpointID, network)
 
 	if err != nil {
 		return diag.FromErr(err)
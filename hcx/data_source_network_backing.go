@@ -57,7 +57,7 @@ func dataSourceNetworkBackingRead(ctx context.Context, d *schema.ResourceData, m
 	vcUUID := d.Get("vcuuid").(string)
 	networkType := d.Get("network_type").(string)
 
-	res, err := GetNetworkBacking(client, vcUUID, network, networkType)
+	res, err := GetNetworkBacking(ctx, client, vcUUID, network, networkType)
 
 	if err != nil {
 		return diag.FromErr(err)
@@ -0,0 +1,232 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched by HCXError through errors.Is, for the conditions resources commonly branch on.
+var (
+	ErrNotFound             = errors.New("not found")
+	ErrLoginFailure         = errors.New("login failure")
+	ErrCertificateUntrusted = errors.New("certificate untrusted")
+	ErrConflict             = errors.New("conflict")
+)
+
+// HCXError represents an error reported by the HCX API, either through a non-successful HTTP status or through an
+// error payload in the response body.
+type HCXError struct {
+	StatusCode int
+	Method     string
+	Endpoint   string
+	Code       string
+	Message    string
+	Data       []map[string]interface{}
+	Body       string
+}
+
+// hcxErrorPayload represents the JSON error formats returned by the HCX APIs.
+type hcxErrorPayload struct {
+	Errors    []hcxErrorItem `json:"errors"`
+	Error     string         `json:"error"`
+	ErrorCode string         `json:"errorCode"`
+	Code      string         `json:"code"`
+	Message   string         `json:"message"`
+	Text      string         `json:"text"`
+}
+
+// hcxErrorItem represents a single entry in the errors list of an HCX API response.
+type hcxErrorItem struct {
+	Error   string                   `json:"error"`
+	Code    string                   `json:"code"`
+	Text    string                   `json:"text"`
+	Message string                   `json:"message"`
+	Data    []map[string]interface{} `json:"data"`
+}
+
+// Error returns a readable description of the HCX API error.
+func (e *HCXError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("HCX API error")
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, " (status %d)", e.StatusCode)
+	}
+	if e.Endpoint != "" {
+		fmt.Fprintf(&b, " on %s %s", e.Method, e.Endpoint)
+	}
+
+	switch {
+	case e.Code != "" && e.Message != "" && e.Code != e.Message:
+		fmt.Fprintf(&b, ": %s: %s", e.Code, e.Message)
+	case e.Message != "":
+		fmt.Fprintf(&b, ": %s", e.Message)
+	case e.Code != "":
+		fmt.Fprintf(&b, ": %s", e.Code)
+	case e.Body != "":
+		fmt.Fprintf(&b, ": %s", e.Body)
+	}
+
+	return b.String()
+}
+
+// Is reports whether the error matches one of the sentinel errors ErrNotFound, ErrLoginFailure,
+// ErrCertificateUntrusted or ErrConflict.
+func (e *HCXError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.IsNotFound()
+	case ErrLoginFailure:
+		return e.IsLoginFailure()
+	case ErrCertificateUntrusted:
+		return e.IsCertificateUntrusted()
+	case ErrConflict:
+		return e.IsConflict()
+	}
+
+	return false
+}
+
+// IsNotFound reports whether the requested object does not exist, as reported by a 404 status. The message is not
+// matched, since it may refer to another object, such as a job or a network backing used by the request.
+func (e *HCXError) IsNotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsLoginFailure reports whether the credentials were rejected.
+func (e *HCXError) IsLoginFailure() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.contains("login failure", "authentication failed")
+}
+
+// IsCertificateUntrusted reports whether the operation failed because a certificate is not trusted. When the HCX API
+// returns the untrusted certificate, it is available through Certificate.
+func (e *HCXError) IsCertificateUntrusted() bool {
+	return e.Certificate() != "" || e.contains("trusted root certificates", "certificate is not trusted", "untrusted certificate")
+}
+
+// IsSSONotReady reports whether the login was rejected because the SSO configuration of the HCX Connector does not
+// have its trusted root certificates yet, which happens for a short time after the appliance starts.
+func (e *HCXError) IsSSONotReady() bool {
+	return strings.EqualFold(strings.TrimSpace(e.Message), "'Trusted root certificates' value should not be empty")
+}
+
+// IsConflict reports whether the operation conflicts with an existing object.
+func (e *HCXError) IsConflict() bool {
+	return e.StatusCode == http.StatusConflict || e.contains("already exists", "duplicate")
+}
+
+// Certificate returns the PEM certificate included in the error data, if any.
+func (e *HCXError) Certificate() string {
+	for _, d := range e.Data {
+		if certificate, ok := d["certificate"].(string); ok && certificate != "" {
+			return certificate
+		}
+	}
+
+	return ""
+}
+
+// contains reports whether the error code or message contains any of the provided substrings, ignoring case.
+func (e *HCXError) contains(substrings ...string) bool {
+	text := strings.ToLower(e.Code + " " + e.Message)
+	for _, s := range substrings {
+		if strings.Contains(text, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newHCXError builds an HCXError from a failed HTTP exchange, decoding the JSON or XML error payload of the body when
+// present.
+func newHCXError(req *http.Request, statusCode int, body []byte) *HCXError {
+	e := &HCXError{
+		StatusCode: statusCode,
+		Body:       strings.TrimSpace(string(body)),
+	}
+
+	if req != nil {
+		e.Method = req.Method
+		e.Endpoint = req.URL.Path
+	}
+
+	if !e.decodeJSON(body) {
+		e.decodeXML(body)
+	}
+
+	return e
+}
+
+// decodeJSON fills the error code, message and data from a JSON error payload. Returns false if the body is not a
+// JSON error payload.
+func (e *HCXError) decodeJSON(body []byte) bool {
+	var p hcxErrorPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return false
+	}
+
+	if len(p.Errors) > 0 {
+		e.fromItems(p.Errors)
+		return true
+	}
+
+	e.Code = firstNonEmpty(p.ErrorCode, p.Code, p.Error)
+	e.Message = firstNonEmpty(p.Message, p.Text)
+
+	return e.Code != "" || e.Message != ""
+}
+
+// decodeXML fills the error code and message from the key/value XML entries format used by the HCX session API.
+func (e *HCXError) decodeXML(body []byte) bool {
+	var entries Entries
+	if err := xml.Unmarshal(body, &entries); err != nil {
+		return false
+	}
+
+	values := map[string]string{}
+	for _, entry := range entries.Entry {
+		if len(entry.Strings) >= 2 {
+			values[entry.Strings[0]] = entry.Strings[1]
+		}
+	}
+
+	e.Code = firstNonEmpty(values["errorCode"], values["code"], values["error"])
+	e.Message = firstNonEmpty(values["message"], values["text"])
+
+	return e.Code != "" || e.Message != ""
+}
+
+// fromItems fills the error code, message and data from the errors list of an HCX API response. The first item
+// provides the code, and the messages of all items are joined.
+func (e *HCXError) fromItems(items []hcxErrorItem) {
+	messages := []string{}
+	for _, item := range items {
+		if m := firstNonEmpty(item.Text, item.Message); m != "" {
+			messages = append(messages, m)
+		}
+		e.Data = append(e.Data, item.Data...)
+	}
+
+	e.Code = firstNonEmpty(items[0].Error, items[0].Code)
+	e.Message = strings.Join(messages, "; ")
+}
+
+// firstNonEmpty returns the first non-empty string of the provided values.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,111 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+)
+
+// cidrIPScope derives the IP scope of a network profile from a CIDR block. The gateway defaults to the first usable
+// address of the block. The pool starts at offset addresses from the network address, or right after the gateway when
+// offset is zero, and holds size addresses, or runs to the last usable address when size is zero. The gateway is left
+// out of the pool, which is then extended by one address to still hold size addresses, and split in two ranges if
+// needed.
+func cidrIPScope(cidr, gateway string, offset, size int) (IPScope, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return IPScope{}, fmt.Errorf("invalid CIDR block %s: %w", cidr, err)
+	}
+	prefix = prefix.Masked()
+
+	first, last := usableAddresses(prefix)
+
+	gw := first
+	if gateway != "" {
+		gw, err = netip.ParseAddr(gateway)
+		if err != nil {
+			return IPScope{}, fmt.Errorf("invalid gateway %s: %w", gateway, err)
+		}
+		if !prefix.Contains(gw) {
+			return IPScope{}, fmt.Errorf("gateway %s is not inside the CIDR block %s", gw, prefix)
+		}
+	}
+
+	start := first
+	if offset > 0 {
+		start = addAddr(prefix.Addr(), big.NewInt(int64(offset)))
+	} else if gw == first {
+		start = gw.Next()
+	}
+
+	end := last
+	if size > 0 {
+		end = addAddr(start, big.NewInt(int64(size-1)))
+		if end.IsValid() && gw.Compare(start) >= 0 && gw.Compare(end) <= 0 {
+			end = end.Next()
+		}
+	}
+
+	if !start.IsValid() || !end.IsValid() || start.Compare(first) < 0 || end.Compare(last) > 0 || start.Compare(end) > 0 {
+		return IPScope{}, fmt.Errorf("the pool of %d addresses at offset %d does not fit in the usable addresses %s-%s of "+
+			"the CIDR block %s", size, offset, first, last, prefix)
+	}
+
+	ranges := []NetworkIPRange{}
+	switch {
+	case gw.Compare(start) < 0 || gw.Compare(end) > 0:
+		ranges = append(ranges, NetworkIPRange{StartAddress: start.String(), EndAddress: end.String()})
+	case gw == start && gw == end:
+		return IPScope{}, fmt.Errorf("the pool of the CIDR block %s only holds the gateway %s", prefix, gw)
+	case gw == start:
+		ranges = append(ranges, NetworkIPRange{StartAddress: gw.Next().String(), EndAddress: end.String()})
+	case gw == end:
+		ranges = append(ranges, NetworkIPRange{StartAddress: start.String(), EndAddress: gw.Prev().String()})
+	default:
+		ranges = append(ranges,
+			NetworkIPRange{StartAddress: start.String(), EndAddress: gw.Prev().String()},
+			NetworkIPRange{StartAddress: gw.Next().String(), EndAddress: end.String()})
+	}
+
+	return IPScope{
+		Gateway:         gw.String(),
+		PrefixLength:    prefix.Bits(),
+		NetworkIPRanges: ranges,
+	}, nil
+}
+
+// usableAddresses returns the first and last usable addresses of the prefix. The network and broadcast addresses of
+// an IPv4 prefix are not usable, except for /31 and /32 prefixes. The subnet-router anycast address of an IPv6 prefix
+// is not usable, except for /128 prefixes.
+func usableAddresses(prefix netip.Prefix) (netip.Addr, netip.Addr) {
+	first := prefix.Addr()
+	hostBits := first.BitLen() - prefix.Bits()
+
+	count := new(big.Int).Lsh(big.NewInt(1), uint(hostBits))
+	last := addAddr(first, count.Sub(count, big.NewInt(1)))
+
+	switch {
+	case first.Is4() && hostBits > 1:
+		return first.Next(), last.Prev()
+	case first.Is6() && hostBits > 0:
+		return first.Next(), last
+	}
+
+	return first, last
+}
+
+// addAddr returns the address n addresses after addr, or the zero address if the result overflows.
+func addAddr(addr netip.Addr, n *big.Int) netip.Addr {
+	b := addr.AsSlice()
+	sum := new(big.Int).Add(new(big.Int).SetBytes(b), n)
+	if sum.BitLen() > len(b)*8 {
+		return netip.Addr{}
+	}
+
+	res, _ := netip.AddrFromSlice(sum.FillBytes(make([]byte, len(b))))
+	return res
+}
@@ -0,0 +1,174 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"math/big"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestCidrIPScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidr    string
+		gateway string
+		offset  int
+		size    int
+		want    IPScope
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			cidr: "10.0.0.0/24",
+			want: IPScope{Gateway: "10.0.0.1", PrefixLength: 24, NetworkIPRanges: []NetworkIPRange{
+				{StartAddress: "10.0.0.2", EndAddress: "10.0.0.254"},
+			}},
+		},
+		{
+			name:   "unmasked CIDR block",
+			cidr:   "10.0.0.77/24",
+			offset: 100,
+			size:   20,
+			want: IPScope{Gateway: "10.0.0.1", PrefixLength: 24, NetworkIPRanges: []NetworkIPRange{
+				{StartAddress: "10.0.0.100", EndAddress: "10.0.0.119"},
+			}},
+		},
+		{
+			name:   "gateway at the start of the pool",
+			cidr:   "10.0.0.0/24",
+			offset: 1,
+			size:   10,
+			want: IPScope{Gateway: "10.0.0.1", PrefixLength: 24, NetworkIPRanges: []NetworkIPRange{
+				{StartAddress: "10.0.0.2", EndAddress: "10.0.0.11"},
+			}},
+		},
+		{
+			name:    "gateway inside the pool",
+			cidr:    "10.0.0.0/24",
+			gateway: "10.0.0.5",
+			offset:  1,
+			size:    10,
+			want: IPScope{Gateway: "10.0.0.5", PrefixLength: 24, NetworkIPRanges: []NetworkIPRange{
+				{StartAddress: "10.0.0.1", EndAddress: "10.0.0.4"},
+				{StartAddress: "10.0.0.6", EndAddress: "10.0.0.11"},
+			}},
+		},
+		{
+			name:    "gateway at the end of the pool",
+			cidr:    "10.0.0.0/24",
+			gateway: "10.0.0.254",
+			offset:  250,
+			want: IPScope{Gateway: "10.0.0.254", PrefixLength: 24, NetworkIPRanges: []NetworkIPRange{
+				{StartAddress: "10.0.0.250", EndAddress: "10.0.0.253"},
+			}},
+		},
+		{
+			name: "IPv4 /31",
+			cidr: "10.0.0.0/31",
+			want: IPScope{Gateway: "10.0.0.0", PrefixLength: 31, NetworkIPRanges: []NetworkIPRange{
+				{StartAddress: "10.0.0.1", EndAddress: "10.0.0.1"},
+			}},
+		},
+		{
+			name:    "IPv4 /32",
+			cidr:    "10.0.0.0/32",
+			wantErr: true,
+		},
+		{
+			name: "IPv6",
+			cidr: "fd00::/64",
+			want: IPScope{Gateway: "fd00::1", PrefixLength: 64, NetworkIPRanges: []NetworkIPRange{
+				{StartAddress: "fd00::2", EndAddress: "fd00::ffff:ffff:ffff:ffff"},
+			}},
+		},
+		{
+			name:   "IPv6 pool",
+			cidr:   "fd00::/64",
+			offset: 256,
+			size:   16,
+			want: IPScope{Gateway: "fd00::1", PrefixLength: 64, NetworkIPRanges: []NetworkIPRange{
+				{StartAddress: "fd00::100", EndAddress: "fd00::10f"},
+			}},
+		},
+		{
+			name:    "pool beyond the CIDR block",
+			cidr:    "10.0.0.0/24",
+			offset:  250,
+			size:    10,
+			wantErr: true,
+		},
+		{
+			name:    "pool overflowing the address space",
+			cidr:    "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/120",
+			offset:  250,
+			size:    10,
+			wantErr: true,
+		},
+		{
+			name:    "gateway outside the CIDR block",
+			cidr:    "10.0.0.0/24",
+			gateway: "10.0.1.1",
+			wantErr: true,
+		},
+		{
+			name:    "invalid CIDR block",
+			cidr:    "10.0.0.0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cidrIPScope(tt.cidr, tt.gateway, tt.offset, tt.size)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("cidrIPScope() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cidrIPScope() returned an error: %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cidrIPScope() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsableAddresses(t *testing.T) {
+	tests := []struct {
+		prefix      string
+		first, last string
+	}{
+		{prefix: "10.0.0.0/24", first: "10.0.0.1", last: "10.0.0.254"},
+		{prefix: "10.0.0.0/31", first: "10.0.0.0", last: "10.0.0.1"},
+		{prefix: "10.0.0.0/32", first: "10.0.0.0", last: "10.0.0.0"},
+		{prefix: "0.0.0.0/0", first: "0.0.0.1", last: "255.255.255.254"},
+		{prefix: "fd00::/64", first: "fd00::1", last: "fd00::ffff:ffff:ffff:ffff"},
+		{prefix: "fd00::/127", first: "fd00::1", last: "fd00::1"},
+		{prefix: "fd00::/128", first: "fd00::", last: "fd00::"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			first, last := usableAddresses(netip.MustParsePrefix(tt.prefix))
+			if first.String() != tt.first || last.String() != tt.last {
+				t.Errorf("usableAddresses() = %s, %s, want %s, %s", first, last, tt.first, tt.last)
+			}
+		})
+	}
+}
+
+func TestAddAddrOverflow(t *testing.T) {
+	if got := addAddr(netip.MustParseAddr("255.255.255.255"), big.NewInt(1)); got.IsValid() {
+		t.Errorf("addAddr() = %s, want the zero address", got)
+	}
+	if got := addAddr(netip.MustParseAddr("10.0.0.255"), big.NewInt(1)); got.String() != "10.0.1.0" {
+		t.Errorf("addAddr() = %s, want 10.0.1.0", got)
+	}
+}
@@ -5,6 +5,7 @@
 package hcx
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -17,11 +18,11 @@ type AppEngineStartStopResult struct {
 
 // AppEngineStart sends a request to start the App Engine component and returns the resulting AppEngineStartStopResult
 // object. Returns an error if the request fails or the response cannot be parsed.
-func AppEngineStart(c *Client) (AppEngineStartStopResult, error) {
+func AppEngineStart(ctx context.Context, c *Client) (AppEngineStartStopResult, error) {
 
 	resp := AppEngineStartStopResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s:9443/components/appengine?action=start", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s:9443/components/appengine?action=start", c.HostURL), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -41,11 +42,11 @@ func AppEngineStart(c *Client) (AppEngineStartStopResult, error) {
 
 // AppEngineStop sends a request to stop the App Engine component and returns the resulting AppEngineStartStopResult
 // object. Returns an error if the request fails or the response cannot be parsed.
-func AppEngineStop(c *Client) (AppEngineStartStopResult, error) {
+func AppEngineStop(ctx context.Context, c *Client) (AppEngineStartStopResult, error) {
 
 	resp := AppEngineStartStopResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s:9443/components/appengine?action=stop", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s:9443/components/appengine?action=stop", c.HostURL), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -65,11 +66,11 @@ func AppEngineStop(c *Client) (AppEngineStartStopResult, error) {
 
 // GetAppEngineStatus sends a GET request to retrieve the current status of the App Engine component and returns the
 // resulting AppEngineStartStopResult object. Returns an error if the request fails or the response cannot be parsed.
-func GetAppEngineStatus(c *Client) (AppEngineStartStopResult, error) {
+func GetAppEngineStatus(ctx context.Context, c *Client) (AppEngineStartStopResult, error) {
 
 	resp := AppEngineStartStopResult{}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s:9443/components/appengine/status", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s:9443/components/appengine/status", c.HostURL), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -71,9 +72,20 @@ type GetL2ExtensionsResult struct {
 
 // GetL2ExtensionsResultItem represents an item in the result of a Layer 2 extensions request.
 type GetL2ExtensionsResultItem struct {
-	StretchID       string          `json:"stretchId"`
-	OperationStatus OperationStatus `json:"operationStatus"`
-	SourceNetwork   SourceNetwork   `json:"sourceNetwork"`
+	StretchID          string             `json:"stretchId"`
+	Gateway            string             `json:"gateway"`
+	Netmask            string             `json:"netmask"`
+	Features           Features           `json:"features"`
+	OperationStatus    OperationStatus    `json:"operationStatus"`
+	SourceAppliance    SourceAppliance    `json:"sourceAppliance"`
+	SourceNetwork      SourceNetwork      `json:"sourceNetwork"`
+	DestinationNetwork DestinationNetwork `json:"destinationNetwork"`
+}
+
+// UpdateL2ExtensionBody represents the request body structure for editing a Layer 2 extension.
+type UpdateL2ExtensionBody struct {
+	Features        Features        `json:"features"`
+	SourceAppliance SourceAppliance `json:"sourceAppliance"`
 }
 
 // OperationStatus represents the status of an operation.
@@ -88,7 +100,7 @@ type DeleteL2ExtensionResult struct {
 
 // InsertL2Extension sends a POST request to create a new L2 extension using the provided body and returns the resulting
 // InsertL2ExtensionResult object. Returns an error if the request fails or the response cannot be parsed.
-func InsertL2Extension(c *Client, body InsertL2ExtensionBody) (InsertL2ExtensionResult, error) {
+func InsertL2Extension(ctx context.Context, c *Client, body InsertL2ExtensionBody) (InsertL2ExtensionResult, error) {
 
 	resp := InsertL2ExtensionResult{}
 
@@ -98,7 +110,7 @@ func InsertL2Extension(c *Client, body InsertL2ExtensionBody) (InsertL2Extension
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/l2Extensions", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/l2Extensions", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -119,11 +131,11 @@ func InsertL2Extension(c *Client, body InsertL2ExtensionBody) (InsertL2Extension
 // GetL2Extensions sends a GET request to retrieve a list of L2 extensions and returns the resulting
 // GetL2ExtensionsResultItem object matching the given networkName. Returns an error if the request fails, the response
 // cannot be parsed, or no matching L2 extension is found.
-func GetL2Extensions(c *Client, networkName string) (GetL2ExtensionsResultItem, error) {
+func GetL2Extensions(ctx context.Context, c *Client, networkName string) (GetL2ExtensionsResultItem, error) {
 
 	resp := GetL2ExtensionsResult{}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/hybridity/api/l2Extensions", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/hybridity/api/l2Extensions", c.HostURL), nil)
 	if err != nil {
 		return GetL2ExtensionsResultItem{}, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -144,16 +156,75 @@ func GetL2Extensions(c *Client, networkName string) (GetL2ExtensionsResultItem,
 		}
 	}
 
-	return GetL2ExtensionsResultItem{}, fmt.Errorf("cannot find L2 extension for network name: %s", networkName)
+	return GetL2ExtensionsResultItem{}, fmt.Errorf("L2 extension for network name %s: %w", networkName, ErrNotFound)
+}
+
+// GetL2Extension sends a GET request to retrieve the L2 extension identified by stretchID. Returns an error wrapping
+// ErrNotFound if the L2 extension does not exist.
+func GetL2Extension(ctx context.Context, c *Client, stretchID string) (GetL2ExtensionsResultItem, error) {
+
+	resp := GetL2ExtensionsResultItem{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/hybridity/api/l2Extensions/%s", c.HostURL, stretchID), nil)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create GET request: %w", err)
+	}
+
+	_, r, err := c.doRequest(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send GET request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	if resp.StretchID == "" {
+		return resp, fmt.Errorf("L2 extension %s: %w", stretchID, ErrNotFound)
+	}
+
+	return resp, nil
+}
+
+// UpdateL2Extension sends a PUT request to edit the features and the appliance of the L2 extension identified by
+// stretchID and returns the resulting InsertL2ExtensionResult object. Returns an error if the request fails or the
+// response cannot be parsed.
+func UpdateL2Extension(ctx context.Context, c *Client, stretchID string, body UpdateL2ExtensionBody) (InsertL2ExtensionResult, error) {
+
+	resp := InsertL2ExtensionResult{}
+
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/hybridity/api/l2Extensions/%s", c.HostURL, stretchID), &buf)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create PUT request: %w", err)
+	}
+
+	_, r, err := c.doRequest(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send PUT request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	return resp, nil
 }
 
 // DeleteL2Extension sends a DELETE request to remove an L2 extension with the provided stretchID and returns the
 // resulting DeleteL2ExtensionResult object. Returns an error if the request fails or the response cannot be parsed.
-func DeleteL2Extension(c *Client, stretchID string) (DeleteL2ExtensionResult, error) {
+func DeleteL2Extension(ctx context.Context, c *Client, stretchID string) (DeleteL2ExtensionResult, error) {
 
 	resp := DeleteL2ExtensionResult{}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/hybridity/api/l2Extensions/%s", c.HostURL, stretchID), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/hybridity/api/l2Extensions/%s", c.HostURL, stretchID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create DELETE request: %w", err)
 	}
@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -34,7 +35,7 @@ type GetLocationResult struct {
 // SetLocation sends request to update the location configuration using the provided body. Returns an error if the
 // SetLocation sends request to update the location configuration using the provided body. Returns an error if the
 // request fails or cannot be sent.
-func SetLocation(c *Client, body SetLocationBody) error {
+func SetLocation(ctx context.Context, c *Client, body SetLocationBody) error {
 
 	var buf bytes.Buffer
 	err := json.NewEncoder(&buf).Encode(body)
@@ -42,12 +43,12 @@ func SetLocation(c *Client, body SetLocationBody) error {
 		return fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s:9443/api/admin/global/config/location", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s:9443/api/admin/global/config/location", c.HostURL), &buf)
 	if err != nil {
 		return fmt.Errorf("failed to create PUT request: %w", err)
 	}
 
-	_, _, err = c.doAdminRequest(req)
+	_, _, err = c.doAdminRequest(withIdempotent(req))
 	if err != nil {
 		return fmt.Errorf("failed to send PUT request: %w", err)
 	}
@@ -57,11 +58,11 @@ func SetLocation(c *Client, body SetLocationBody) error {
 
 // GetLocation sends a request to retrieve the current location configuration and returns the resulting
 // GetLocationResult object. Returns an error if the request fails or the response cannot be parsed.
-func GetLocation(c *Client) (GetLocationResult, error) {
+func GetLocation(ctx context.Context, c *Client) (GetLocationResult, error) {
 
 	resp := GetLocationResult{}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s:9443/api/admin/global/config/location", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s:9443/api/admin/global/config/location", c.HostURL), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create GET request: %w", err)
 	}
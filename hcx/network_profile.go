@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -77,7 +78,7 @@ type NetworkProfileData struct {
 
 // InsertNetworkProfile sends a request to create a new network profile using the provided body and returns the
 // resulting NetworkProfileResult object. Returns an error if the request fails or the response cannot be parsed.
-func InsertNetworkProfile(c *Client, body NetworkProfileBody) (NetworkProfileResult, error) {
+func InsertNetworkProfile(ctx context.Context, c *Client, body NetworkProfileBody) (NetworkProfileResult, error) {
 	resp := NetworkProfileResult{}
 
 	var buf bytes.Buffer
@@ -86,7 +87,7 @@ func InsertNetworkProfile(c *Client, body NetworkProfileBody) (NetworkProfileRes
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/admin/hybridity/api/networks", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/admin/hybridity/api/networks", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -104,10 +105,9 @@ func InsertNetworkProfile(c *Client, body NetworkProfileBody) (NetworkProfileRes
 	return resp, nil
 }
 
-// GetNetworkProfile sends a request to query the list of network profiles and returns the NetworkProfileBody object
-// matching the specified name. Returns an error if the request fails, the response cannot be parsed, or no profile is
-// found with the given name.
-func GetNetworkProfile(c *Client, name string) (NetworkProfileBody, error) {
+// GetNetworkProfiles sends a request to query the list of network profiles. Returns an error if the request fails or
+// the response cannot be parsed.
+func GetNetworkProfiles(ctx context.Context, c *Client) ([]NetworkProfileBody, error) {
 	resp := []NetworkProfileBody{}
 	body := NetworkFilter{
 		Filter: Filter{
@@ -119,82 +119,70 @@ func GetNetworkProfile(c *Client, name string) (NetworkProfileBody, error) {
 	var buf bytes.Buffer
 	err := json.NewEncoder(&buf).Encode(body)
 	if err != nil {
-		return NetworkProfileBody{}, fmt.Errorf("failed to encode request body: %w", err)
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/networks?action=queryIpUsage", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/networks?action=queryIpUsage", c.HostURL), &buf)
 	if err != nil {
-		return NetworkProfileBody{}, fmt.Errorf("failed to create POST request: %w", err)
+		return nil, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
-		return NetworkProfileBody{}, fmt.Errorf("failed to send POST request: %w", err)
+		return nil, fmt.Errorf("failed to send POST request: %w", err)
 	}
 
 	err = json.Unmarshal(r, &resp)
 	if err != nil {
-		return NetworkProfileBody{}, fmt.Errorf("failed to parse HTTP response: %w", err)
+		return nil, fmt.Errorf("failed to parse HTTP response: %w", err)
 	}
 
-	for _, j := range resp {
+	return resp, nil
+}
+
+// GetNetworkProfile sends a request to query the list of network profiles and returns the NetworkProfileBody object
+// matching the specified name. Returns an error if the request fails, the response cannot be parsed, or no profile is
+// found with the given name.
+func GetNetworkProfile(ctx context.Context, c *Client, name string) (NetworkProfileBody, error) {
+	profiles, err := GetNetworkProfiles(ctx, c)
+	if err != nil {
+		return NetworkProfileBody{}, err
+	}
+
+	for _, j := range profiles {
 		if j.Name == name {
 			return j, nil
 		}
 	}
 
-	return NetworkProfileBody{}, fmt.Errorf("network profile with name '%s' not found", name)
+	return NetworkProfileBody{}, fmt.Errorf("network profile with name '%s' %w", name, ErrNotFound)
 }
 
 // GetNetworkProfileByID sends a request to query the list of network profiles and returns the NetworkProfileBody object
 // matching the specified ID. Returns an error if the request fails, the response cannot be parsed, or no profile is
 // found with the given ID.
-func GetNetworkProfileByID(c *Client, id string) (NetworkProfileBody, error) {
-	resp := []NetworkProfileBody{}
-	body := NetworkFilter{
-		Filter: Filter{
-			OwnedBySystem:        true,
-			AllowTrunkInterfaces: false,
-		},
-	}
-
-	var buf bytes.Buffer
-	err := json.NewEncoder(&buf).Encode(body)
-	if err != nil {
-		return NetworkProfileBody{}, fmt.Errorf("failed to encode request body: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/networks?action=queryIpUsage", c.HostURL), &buf)
-	if err != nil {
-		return NetworkProfileBody{}, fmt.Errorf("failed to create POST request: %w", err)
-	}
-
-	_, r, err := c.doRequest(req)
-	if err != nil {
-		return NetworkProfileBody{}, fmt.Errorf("failed to send POST request: %w", err)
-	}
-
-	err = json.Unmarshal(r, &resp)
+func GetNetworkProfileByID(ctx context.Context, c *Client, id string) (NetworkProfileBody, error) {
+	profiles, err := GetNetworkProfiles(ctx, c)
 	if err != nil {
-		return NetworkProfileBody{}, fmt.Errorf("failed to parse HTTP response: %w", err)
+		return NetworkProfileBody{}, err
 	}
 
-	for _, j := range resp {
+	for _, j := range profiles {
 		if j.ObjectID == id {
 			return j, nil
 		}
 	}
 
-	return NetworkProfileBody{}, fmt.Errorf("network profile with ID '%s' not found", id)
+	return NetworkProfileBody{}, fmt.Errorf("network profile with ID '%s' %w", id, ErrNotFound)
 }
 
 // DeleteNetworkProfile sends a DELETE request to remove a network profile identified by the provided networkID and
 // returns the resulting NetworkProfileResult object. Returns an error if the request fails or the response cannot be
 // parsed.
-func DeleteNetworkProfile(c *Client, networkID string) (NetworkProfileResult, error) {
+func DeleteNetworkProfile(ctx context.Context, c *Client, networkID string) (NetworkProfileResult, error) {
 	resp := NetworkProfileResult{}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/hybridity/api/networks/%s", c.HostURL, networkID), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/hybridity/api/networks/%s", c.HostURL, networkID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create DELETE request: %w", err)
 	}
@@ -214,7 +202,7 @@ func DeleteNetworkProfile(c *Client, networkID string) (NetworkProfileResult, er
 
 // UpdateNetworkProfile sends a request to update a network profile using the provided body and returns the resulting
 // NetworkProfileResult object. Returns an error if the request fails or the response cannot be parsed.
-func UpdateNetworkProfile(c *Client, body NetworkProfileBody) (NetworkProfileResult, error) {
+func UpdateNetworkProfile(ctx context.Context, c *Client, body NetworkProfileBody) (NetworkProfileResult, error) {
 	resp := NetworkProfileResult{}
 
 	var buf bytes.Buffer
@@ -223,7 +211,7 @@ func UpdateNetworkProfile(c *Client, body NetworkProfileBody) (NetworkProfileRes
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/hybridity/api/networks/%s", c.HostURL, body.ObjectID), &buf)
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/hybridity/api/networks/%s", c.HostURL, body.ObjectID), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create PUT request: %w", err)
 	}
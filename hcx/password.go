@@ -0,0 +1,58 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// passwordSchema returns the schema of the password attribute. Either it or its write-only variant must be set.
+func passwordSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  description,
+		Optional:     true,
+		Sensitive:    true,
+		ExactlyOneOf: []string{"password", "password_wo"},
+	}
+}
+
+// passwordWriteOnlySchema returns the schema of the write-only variant of the password attribute. Its value is never
+// stored in the state, so it is only sent to HCX again when password_wo_version changes.
+func passwordWriteOnlySchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  description + " The value is not stored in the state.",
+		Optional:     true,
+		Sensitive:    true,
+		WriteOnly:    true,
+		ExactlyOneOf: []string{"password", "password_wo"},
+		RequiredWith: []string{"password_wo_version"},
+	}
+}
+
+// passwordWriteOnlyVersionSchema returns the schema of the version of the write-only password.
+func passwordWriteOnlyVersionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Description:  "The version of password_wo. Change it to update the password.",
+		Optional:     true,
+		RequiredWith: []string{"password_wo"},
+	}
+}
+
+// getPassword returns the configured password, read from the configuration when the write-only variant is used.
+func getPassword(d *schema.ResourceData) string {
+	if v := d.GetRawConfig().GetAttr("password_wo"); !v.IsNull() && v.IsKnown() {
+		return v.AsString()
+	}
+
+	return d.Get("password").(string)
+}
+
+// passwordChanged reports whether the password or the version of its write-only variant changed.
+func passwordChanged(d *schema.ResourceData) bool {
+	return d.HasChanges("password", "password_wo_version")
+}
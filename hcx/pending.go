@@ -0,0 +1,108 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// pendingSchema returns the schema of the computed attribute that records the ID of an HCX job or task still running
+// after an interrupted apply.
+func pendingSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeString,
+		Description: fmt.Sprintf("The ID of the HCX %s still running after an interrupted apply. The next apply waits "+
+			"for it to complete.", kind),
+		Computed: true,
+	}
+}
+
+// customizeDiffPending plans an update of the resource while the HCX job or task recorded in key is still running,
+// so that the next apply resumes waiting for it instead of creating the resource again.
+func customizeDiffPending(key string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if d.Id() == "" || d.Get(key).(string) == "" {
+			return nil
+		}
+
+		return d.SetNewComputed(key)
+	}
+}
+
+// pendingID returns the ID of the HCX job or task recorded in key by an interrupted apply. The prior state is used,
+// since the planned value is unknown while the operation is pending.
+func pendingID(d *schema.ResourceData, key string) string {
+	id, _ := d.GetChange(key)
+	return id.(string)
+}
+
+// waitDiagnostics returns the diagnostics for a failed wait on the HCX job or task identified by id. When the wait was
+// interrupted, the operation is still running, so its ID is recorded in key and a warning is returned, so that the
+// resource is saved without being tainted and the next apply resumes waiting for it. A failed or cancelled edit of an
+// existing resource keeps its prior state, except for the operation resumed from key, which is cleared.
+//
+// Only a graceful interrupt is handled. The state is written when an operation returns, so if the provider crashes or
+// is killed while waiting, nothing is recorded and the object is orphaned in HCX; it is only taken over on the next
+// apply when adopt_existing is enabled.
+func waitDiagnostics(d *schema.ResourceData, key, id string, err error) diag.Diagnostics {
+	if !interrupted(err) || d.Id() == "" {
+		if !d.IsNewResource() && pendingID(d, key) != id {
+			d.Partial(true)
+		}
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(key, id); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "HCX operation still running",
+		Detail:   fmt.Sprintf("%s. The next apply resumes waiting for it.", err),
+	}}
+}
+
+// refreshPending checks once the HCX job or task recorded in key by an interrupted apply, using check. When the
+// operation is done, key is cleared, and a warning is returned if it failed or was cancelled. The operation may have
+// been an edit, so the resource is then refreshed from HCX by the caller rather than removed from the state, and only
+// removed if the object does not exist. Returns true if the operation is still running.
+func refreshPending(ctx context.Context, c *Client, d *schema.ResourceData, key string,
+	check func(ctx context.Context, c *Client, id string) (bool, error)) (bool, diag.Diagnostics) {
+	id := pendingID(d, key)
+	if id == "" {
+		return false, nil
+	}
+
+	done, err := check(ctx, c, id)
+
+	var diags diag.Diagnostics
+	var waitErr *WaitError
+	switch {
+	case errors.As(err, &waitErr):
+		log.Printf("[WARN] %s, refreshing %s from HCX", waitErr, d.Id())
+		diags = diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "HCX operation failed",
+			Detail:   fmt.Sprintf("%s. The resource is refreshed from HCX.", waitErr),
+		}}
+	case err != nil:
+		return false, diag.FromErr(err)
+	case !done:
+		return true, nil
+	}
+
+	if err := d.Set(key, ""); err != nil {
+		return false, diag.FromErr(err)
+	}
+
+	return false, diags
+}
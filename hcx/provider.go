@@ -6,10 +6,14 @@ package hcx
 
 import (
 	"context"
+	"time"
+
+	"github.com/vmware/terraform-provider-hcx/hcx/validators"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Provider returns the schema.Provider object configured with resources, data sources, and schema for the HCX provider.
@@ -54,6 +58,26 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("HCX_ALLOW_UNVERIFIED_SSL", false),
 				Description: "Allow SSL connections with unverifiable certificates.",
 			},
+			"ca_file": {
+				Type:        schema.TypeString,
+				Description: "The path to a PEM-encoded CA bundle used to verify the HCX certificates, in addition to the system trust store.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HCX_CA_FILE", ""),
+			},
+			"ca_pem": {
+				Type:        schema.TypeString,
+				Description: "The PEM-encoded CA certificates used to verify the HCX certificates, in addition to the system trust store.",
+				Optional:    true,
+			},
+			"tls_server_fingerprints": {
+				Type:        schema.TypeList,
+				Description: "The SHA-256 fingerprints of the HCX certificates to trust. When set, the connection is only trusted if the server certificate matches one of them.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validators.ValidateSHA256Fingerprint,
+				},
+			},
 			"vmc_token": {
 				Type:        schema.TypeString,
 				Description: "The token to authenticate with the VMware Cloud Services API.",
@@ -61,6 +85,45 @@ func Provider() *schema.Provider {
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("VMC_API_TOKEN", nil),
 			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Description:  "The maximum number of retries for requests that fail with a connection error, a 429 or a 5xx response.",
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("HCX_MAX_RETRIES", DefaultMaxRetries),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_min_wait": {
+				Type:         schema.TypeInt,
+				Description:  "The minimum time to wait between retries, in seconds.",
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("HCX_RETRY_MIN_WAIT", int(DefaultRetryMinWait/time.Second)),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"retry_max_wait": {
+				Type:         schema.TypeInt,
+				Description:  "The maximum time to wait between retries, in seconds.",
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("HCX_RETRY_MAX_WAIT", int(DefaultRetryMaxWait/time.Second)),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Description: "The URL of the HTTP proxy used to reach HCX and VMware Cloud Services. Defaults to the HTTPS_PROXY and HTTP_PROXY environment variables.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HCX_PROXY_URL", ""),
+			},
+			"no_proxy": {
+				Type:        schema.TypeString,
+				Description: "A comma-separated list of hosts, domains and CIDR blocks that bypass the proxy. Defaults to the NO_PROXY environment variable.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HCX_NO_PROXY", ""),
+			},
+			"adopt_existing": {
+				Type:        schema.TypeBool,
+				Description: "Adopt existing network profiles, compute profiles, service meshes and L2 extensions matching the configuration on create, instead of creating new ones.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HCX_ADOPT_EXISTING", false),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"hcx_activation":      resourceActivation(),
@@ -96,12 +159,35 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	allowUnverifiedSSL := d.Get("allow_unverified_ssl").(bool)
 	vmcToken := d.Get("vmc_token").(string)
 
-	c, err := NewClient(&hcxURL, &username, &password, &adminUsername, &adminPassword, &allowUnverifiedSSL, &vmcToken)
+	transportConfig := TransportConfig{
+		ProxyURL: d.Get("proxy_url").(string),
+		NoProxy:  d.Get("no_proxy").(string),
+		CAFile:   d.Get("ca_file").(string),
+		CAPEM:    d.Get("ca_pem").(string),
+	}
+	for _, f := range d.Get("tls_server_fingerprints").([]interface{}) {
+		transportConfig.ServerFingerprints = append(transportConfig.ServerFingerprints, f.(string))
+	}
+
+	c, err := NewClient(&hcxURL, &username, &password, &adminUsername, &adminPassword, &allowUnverifiedSSL, &vmcToken, transportConfig)
 
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
 
+	retryMinWait := time.Duration(d.Get("retry_min_wait").(int)) * time.Second
+	retryMaxWait := time.Duration(d.Get("retry_max_wait").(int)) * time.Second
+	if retryMaxWait < retryMinWait {
+		return nil, diag.Errorf("retry_max_wait (%s) must be greater than or equal to retry_min_wait (%s)", retryMaxWait, retryMinWait)
+	}
+
+	c.Retry = RetryPolicy{
+		MaxRetries: d.Get("max_retries").(int),
+		MinWait:    retryMinWait,
+		MaxWait:    retryMaxWait,
+	}
+	c.AdoptExisting = d.Get("adopt_existing").(bool)
+
 	if hcxURL == "" {
 		diags = append(diags, diag.Diagnostic{
 			Severity:      diag.Warning,
@@ -111,7 +197,7 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		})
 	}
 
-	c.Token = vmcToken
+	c.VmcToken = vmcToken
 
 	return c, diags
 }
@@ -6,6 +6,8 @@ package hcx
 
 import (
 	"context"
+	"fmt"
+	"log"
 
 	"github.com/vmware/terraform-provider-hcx/hcx/constants"
 
@@ -20,6 +22,9 @@ func resourceActivation() *schema.Resource {
 		ReadContext:   resourceActivationRead,
 		UpdateContext: resourceActivationUpdate,
 		DeleteContext: resourceActivationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceActivationImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"url": {
@@ -32,6 +37,7 @@ func resourceActivation() *schema.Resource {
 				Type:        schema.TypeString,
 				Description: "The activation key.",
 				Required:    true,
+				Sensitive:   true,
 			},
 		},
 	}
@@ -59,14 +65,14 @@ func resourceActivationCreate(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	// First, check if already activated
-	res, err := GetActivate(client)
+	res, err := GetActivate(ctx, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	if len(res.Data.Items) == 0 {
 		// No activation config found
-		_, err := PostActivate(client, body)
+		_, err := PostActivate(ctx, client, body)
 
 		if err != nil {
 			return diag.FromErr(err)
@@ -86,13 +92,23 @@ func resourceActivationRead(ctx context.Context, d *schema.ResourceData, m inter
 
 	client := m.(*Client)
 
-	res, err := GetActivate(client)
+	res, err := GetActivate(ctx, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if len(res.Data.Items) == 0 {
+		log.Printf("[WARN] Activation configuration not found, removing from state")
+		d.SetId("")
+		return diags
+	}
+
 	d.SetId(res.Data.Items[0].Config.UUID)
 
+	if err := d.Set("url", res.Data.Items[0].Config.URL); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return diags
 }
 
@@ -108,3 +124,22 @@ func resourceActivationDelete(ctx context.Context, d *schema.ResourceData, m int
 
 	return diags
 }
+
+// resourceActivationImport imports the activation configuration of the HCX Manager. There is a single activation
+// configuration, so the ID given on import is replaced by its UUID.
+func resourceActivationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
+
+	res, err := GetActivate(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Data.Items) == 0 {
+		return nil, fmt.Errorf("activation configuration: %w", ErrNotFound)
+	}
+
+	d.SetId(res.Data.Items[0].Config.UUID)
+
+	return []*schema.ResourceData{d}, nil
+}
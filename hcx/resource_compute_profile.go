@@ -5,10 +5,11 @@
 package hcx
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
 	"time"
 
 	"github.com/vmware/terraform-provider-hcx/hcx/constants"
@@ -24,6 +25,16 @@ func resourceComputeProfile() *schema.Resource {
 		ReadContext:   resourceComputeProfileRead,
 		UpdateContext: resourceComputeProfileUpdate,
 		DeleteContext: resourceComputeProfileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceComputeProfileImport,
+		},
+		CustomizeDiff: customizeDiffPending("pending_task_id"),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -88,6 +99,8 @@ func resourceComputeProfile() *schema.Resource {
 					},
 				},
 			},
+			"pending_task_id": pendingSchema("task"),
+			"adopt_existing":  adoptSchema("name"),
 		},
 	}
 }
@@ -98,13 +111,239 @@ func resourceComputeProfileCreate(ctx context.Context, d *schema.ResourceData, m
 	client := m.(*Client)
 
 	name := d.Get("name").(string)
-	cluster := d.Get("cluster").(string)
 
-	res, err := GetVcInventory(client)
+	// Adopt an existing compute profile with the same name, if enabled.
+	if adoptExisting(d, client) {
+		lc, err := GetLocalCloudList(ctx, client)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(lc.Data.Items) == 0 {
+			return diag.Errorf("cannot find the local HCX endpoint")
+		}
+
+		existing, err := GetComputeProfile(ctx, client, lc.Data.Items[0].EndpointID, name)
+		switch {
+		case err == nil:
+			if err := matchComputeProfile(d, existing); err != nil {
+				return diag.FromErr(err)
+			}
+			d.SetId(existing.ComputeProfileID)
+			return resourceComputeProfileRead(ctx, d, m)
+		case !errors.Is(err, ErrNotFound):
+			return diag.FromErr(err)
+		}
+	}
+
+	body, err := computeProfileBody(ctx, client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res2, err := InsertComputeProfile(ctx, client, body)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Save the compute profile as soon as it exists, so that an interrupted apply can resume waiting for the task.
+	d.SetId(res2.Data.ComputeProfileID)
+
+	// Wait for task completion
+	_, err = WaitForTask(ctx, client, res2.Data.InterconnectTaskID, WaitOptions{Timeout: d.Timeout(schema.TimeoutCreate)})
+	if err != nil {
+		return waitDiagnostics(d, "pending_task_id", res2.Data.InterconnectTaskID, err)
+	}
+
+	return resourceComputeProfileRead(ctx, d, m)
+
+}
+
+// resourceComputeProfileRead retrieves the compute profile configuration.
+func resourceComputeProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	// Refresh the task of an interrupted apply, if any.
+	running, diags := refreshPending(ctx, client, d, "pending_task_id", CheckTask)
+	if running || diags.HasError() || d.Id() == "" {
+		return diags
+	}
+
+	cp, err := GetComputeProfileByID(ctx, client, d.Id())
+	if errors.Is(err, ErrNotFound) {
+		log.Printf("[WARN] Compute profile %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("name", cp.Name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(cp.Compute) > 0 {
+		if err := d.Set("datacenter", cp.Compute[0].Name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if len(cp.DeploymentContainers.Computes) > 0 {
+		if err := d.Set("cluster", cp.DeploymentContainers.Computes[0].Name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if len(cp.DeploymentContainers.Storage) > 0 {
+		if err := d.Set("datastore", cp.DeploymentContainers.Storage[0].Name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if len(cp.Switches) > 0 {
+		if err := d.Set("dvs", cp.Switches[0].Name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// Each network profile is tagged with the traffic types it carries.
+	networks := map[string]string{}
+	for _, n := range cp.Networks {
+		for _, tag := range n.Tags {
+			networks[tag] = n.ID
+		}
+	}
+	for _, tag := range []string{"management", "replication", "uplink", "vmotion"} {
+		if err := d.Set(tag+"_network", networks[tag]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := setServices(d, cp.Services); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// resourceComputeProfileUpdate updates the compute profile configuration.
+func resourceComputeProfileUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	client := m.(*Client)
+
+	// Resume waiting for the task of an interrupted apply.
+	if taskID := pendingID(d, "pending_task_id"); taskID != "" {
+		_, err := WaitForTask(ctx, client, taskID, WaitOptions{Timeout: d.Timeout(schema.TimeoutUpdate)})
+		if err != nil {
+			return waitDiagnostics(d, "pending_task_id", taskID, err)
+		}
+
+		if err := d.Set("pending_task_id", ""); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var diags diag.Diagnostics
+	if d.HasChangesExcept("pending_task_id", "adopt_existing") {
+		body, err := computeProfileBody(ctx, client, d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		res, err := UpdateComputeProfile(ctx, client, d.Id(), body)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// Wait for task completion
+		_, err = WaitForTask(ctx, client, res.Data.InterconnectTaskID, WaitOptions{Timeout: d.Timeout(schema.TimeoutUpdate)})
+		if err != nil {
+			return waitDiagnostics(d, "pending_task_id", res.Data.InterconnectTaskID, err)
+		}
+
+		// The appliances of the service meshes are only redeployed by a resync, which is needed when their placement
+		// or their networks change.
+		if d.HasChanges("cluster", "datastore", "dvs", "management_network", "replication_network", "uplink_network",
+			"vmotion_network") {
+			diags = resyncServiceMeshes(ctx, client, d)
+		}
+	}
+
+	return append(diags, resourceComputeProfileRead(ctx, d, m)...)
+}
+
+// resyncServiceMeshes resyncs the service meshes using the compute profile and waits for their tasks to complete. The
+// compute profile is already updated at this point, so a failed resync is reported as a warning naming the service
+// mesh.
+func resyncServiceMeshes(ctx context.Context, client *Client, d *schema.ResourceData) diag.Diagnostics {
+	meshes, err := GetServiceMeshes(ctx, client)
+	if err != nil {
+		return diag.Diagnostics{resyncWarning("the service meshes using the compute profile", err)}
+	}
+
+	var diags diag.Diagnostics
+
+	for _, sm := range meshes {
+		if !slices.ContainsFunc(sm.ComputeProfiles, func(cp ComputeProfile) bool {
+			return cp.ComputeProfileID == d.Id()
+		}) {
+			continue
+		}
+
+		log.Printf("[INFO] Resyncing service mesh %q after the update of compute profile %s", sm.Name, d.Id())
+		res, err := ResyncServiceMesh(ctx, client, sm)
+		if err == nil {
+			_, err = WaitForTask(ctx, client, res.Data.InterconnectID, WaitOptions{Timeout: d.Timeout(schema.TimeoutUpdate)})
+		}
+		if err != nil {
+			diags = append(diags, resyncWarning(fmt.Sprintf("service mesh %q", sm.Name), err))
+			if interrupted(err) {
+				break
+			}
+		}
+	}
+
+	return diags
+}
+
+// resyncWarning returns the warning for a failed resync of the service meshes described by what.
+func resyncWarning(what string, err error) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Service mesh not resynced",
+		Detail: fmt.Sprintf("The compute profile was updated, but resyncing %s failed: %s. Resync it in HCX so that "+
+			"its appliances pick up the change.", what, err),
+	}
+}
+
+// resourceComputeProfileDelete removes the compute profile configuration and clears the state of the resource in the
+// schema.
+func resourceComputeProfileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(*Client)
+
+	res, err := DeleteComputeProfile(ctx, client, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// Wait for task completion
+	_, err = WaitForTask(ctx, client, res.Data.InterconnectTaskID, WaitOptions{Timeout: d.Timeout(schema.TimeoutDelete)})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// computeProfileBody builds the body used to create or edit the compute profile from the resource configuration.
+func computeProfileBody(ctx context.Context, client *Client, d *schema.ResourceData) (InsertComputeProfileBody, error) {
+	name := d.Get("name").(string)
+	cluster := d.Get("cluster").(string)
+
+	res, err := GetVcInventory(ctx, client)
+	if err != nil {
+		return InsertComputeProfileBody{}, err
+	}
+
 	// Get Cluster info
 	var clusterID string
 	var clusterName string
@@ -117,21 +356,21 @@ func resourceComputeProfileCreate(ctx context.Context, d *schema.ResourceData, m
 		}
 	}
 	if !found {
-		return diag.FromErr(errors.New("cluster not found"))
+		return InsertComputeProfileBody{}, errors.New("cluster not found")
 	}
 
 	// Get Datastore info
 	datastore := d.Get("datastore").(string)
-	datastoreFromAPI, err := GetVcDatastore(client, datastore, res.EntityID, clusterID)
+	datastoreFromAPI, err := GetVcDatastore(ctx, client, datastore, res.EntityID, clusterID)
 	if err != nil {
-		return diag.FromErr(err)
+		return InsertComputeProfileBody{}, err
 	}
 
 	// Get DVS info
 	dvs := d.Get("dvs").(string)
-	dvsFromAPI, err := GetVcDvs(client, dvs, res.EntityID, clusterID)
+	dvsFromAPI, err := GetVcDvs(ctx, client, dvs, res.EntityID, clusterID)
 	if err != nil {
-		return diag.FromErr(err)
+		return InsertComputeProfileBody{}, err
 	}
 
 	// Get Services from schema
@@ -154,30 +393,30 @@ func resourceComputeProfileCreate(ctx context.Context, d *schema.ResourceData, m
 	vmotionNetwork := d.Get("vmotion_network").(string)
 
 	networksList := []Network{}
-	np, err := GetNetworkProfileByID(client, managementNetwork)
+	np, err := GetNetworkProfileByID(ctx, client, managementNetwork)
 	if err != nil {
-		return diag.FromErr(err)
+		return InsertComputeProfileBody{}, err
 	}
 	managementNetworkName := np.Name
 	managementNetworkID := np.ObjectID
 
-	np, err = GetNetworkProfileByID(client, replicationNetwork)
+	np, err = GetNetworkProfileByID(ctx, client, replicationNetwork)
 	if err != nil {
-		return diag.FromErr(err)
+		return InsertComputeProfileBody{}, err
 	}
 	replicationNetworkName := np.Name
 	replicationNetworkID := np.ObjectID
 
-	np, err = GetNetworkProfileByID(client, uplinkNetwork)
+	np, err = GetNetworkProfileByID(ctx, client, uplinkNetwork)
 	if err != nil {
-		return diag.FromErr(err)
+		return InsertComputeProfileBody{}, err
 	}
 	uplinkNetworkName := np.Name
 	uplinkNetworkID := np.ObjectID
 
-	np, err = GetNetworkProfileByID(client, vmotionNetwork)
+	np, err = GetNetworkProfileByID(ctx, client, vmotionNetwork)
 	if err != nil {
-		return diag.FromErr(err)
+		return InsertComputeProfileBody{}, err
 	}
 	vmotionNetworkName := np.Name
 	vmotionNetworkID := np.ObjectID
@@ -301,82 +540,92 @@ func resourceComputeProfileCreate(ctx context.Context, d *schema.ResourceData, m
 		}},
 	}
 
-	buf := new(bytes.Buffer)
-	if err := json.NewEncoder(buf).Encode(body); err != nil {
-		return diag.FromErr(err)
-	}
+	return body, nil
+}
+
+// resourceComputeProfileImport imports a compute profile of the local HCX endpoint by its ID or its name.
+func resourceComputeProfileImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
 
-	res2, err := InsertComputeProfile(client, body)
+	lc, err := GetLocalCloudList(ctx, client)
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
+	}
+	if len(lc.Data.Items) == 0 {
+		return nil, errors.New("cannot find the local HCX endpoint")
 	}
 
-	// Wait for task completion
-	for {
-		jr, err := GetTaskResult(client, res2.Data.InterconnectTaskID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		if jr.Status == constants.SuccessStatus {
-			break
-		}
+	profiles, err := GetComputeProfiles(ctx, client, lc.Data.Items[0].EndpointID)
+	if err != nil {
+		return nil, err
+	}
 
-		if jr.Status == constants.FailedStatus {
-			return diag.FromErr(errors.New("task failed"))
+	for _, cp := range profiles {
+		if cp.ComputeProfileID == d.Id() || cp.Name == d.Id() {
+			d.SetId(cp.ComputeProfileID)
+			return []*schema.ResourceData{d}, nil
 		}
-
-		time.Sleep(5 * time.Second)
 	}
 
-	d.SetId(res2.Data.ComputeProfileID)
-
-	return resourceComputeProfileRead(ctx, d, m)
-
+	return nil, fmt.Errorf("compute profile %s: %w", d.Id(), ErrNotFound)
 }
 
-// resourceComputeProfileRead retrieves the compute profile configuration.
-func resourceComputeProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
+// matchComputeProfile returns an error if the existing compute profile does not match the configuration.
+func matchComputeProfile(d *schema.ResourceData, cp GetComputeProfileResultItem) error {
+	a := adoption{kind: "compute profile", name: cp.Name}
 
-	return diags
-}
+	clusters := []string{}
+	for _, c := range cp.DeploymentContainers.Computes {
+		clusters = append(clusters, c.Name)
+	}
+	a.compareSet("cluster", []string{d.Get("cluster").(string)}, clusters)
 
-// resourceComputeProfileUpdate updates the compute profile configuration.
-func resourceComputeProfileUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	datastores := []string{}
+	for _, s := range cp.DeploymentContainers.Storage {
+		datastores = append(datastores, s.Name)
+	}
+	a.compareSet("datastore", []string{d.Get("datastore").(string)}, datastores)
 
-	return resourceComputeProfileRead(ctx, d, m)
-}
+	switches := []string{}
+	for _, s := range cp.Switches {
+		switches = append(switches, s.Name)
+	}
+	a.compareSet("dvs", []string{d.Get("dvs").(string)}, switches)
 
-// resourceComputeProfileDelete removes the compute profile configuration and clears the state of the resource in the
-// schema.
-func resourceComputeProfileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
+	configured := []string{}
+	for _, j := range d.Get("service").([]interface{}) {
+		configured = append(configured, j.(map[string]interface{})["name"].(string))
+	}
+	existing := []string{}
+	for _, s := range cp.Services {
+		existing = append(existing, s.Name)
+	}
+	a.compareSet("service", configured, existing)
 
-	client := m.(*Client)
+	return a.err()
+}
 
-	res, err := DeleteComputeProfile(client, d.Id())
-	if err != nil {
-		return diag.FromErr(err)
+// setServices sets the service blocks of a resource from the services returned by HCX. The configured order is kept
+// when the services are the same, since HCX does not preserve it.
+func setServices(d *schema.ResourceData, services []Service) error {
+	names := []string{}
+	for _, s := range services {
+		names = append(names, s.Name)
 	}
 
-	// Wait for task completion
-	for {
-		jr, err := GetTaskResult(client, res.Data.InterconnectTaskID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		if jr.Status == constants.SuccessStatus {
-			break
-		}
+	current := []string{}
+	for _, j := range d.Get("service").([]interface{}) {
+		current = append(current, j.(map[string]interface{})["name"].(string))
+	}
 
-		if jr.Status == constants.FailedStatus {
-			return diag.FromErr(errors.New("task failed"))
-		}
+	if slices.Equal(slices.Sorted(slices.Values(names)), slices.Sorted(slices.Values(current))) {
+		return nil
+	}
 
-		time.Sleep(5 * time.Second)
+	tmp := []map[string]string{}
+	for _, name := range names {
+		tmp = append(tmp, map[string]string{"name": name})
 	}
 
-	return diags
+	return d.Set("service", tmp)
 }
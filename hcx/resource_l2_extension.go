@@ -8,13 +8,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/vmware/terraform-provider-hcx/hcx/constants"
 	"github.com/vmware/terraform-provider-hcx/hcx/validators"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -25,6 +28,28 @@ func resourceL2Extension() *schema.Resource {
 		ReadContext:   resourceL2ExtensionRead,
 		UpdateContext: resourceL2ExtensionUpdate,
 		DeleteContext: resourceL2ExtensionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffPending("pending_job_id"),
+			// The site pairing and the service mesh are not imported, so only a change of a known value forces a new
+			// L2 extension.
+			customdiff.ForceNewIfChange("site_pairing", func(ctx context.Context, old, new, meta interface{}) bool {
+				oldID, _ := old.(map[string]interface{})["id"].(string)
+				newID, _ := new.(map[string]interface{})["id"].(string)
+				return oldID != "" && oldID != newID
+			}),
+			customdiff.ForceNewIfChange("service_mesh_id", func(ctx context.Context, old, new, meta interface{}) bool {
+				return old.(string) != "" && old.(string) != new.(string)
+			}),
+		),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"site_pairing": {
@@ -41,30 +66,35 @@ func resourceL2Extension() *schema.Resource {
 				Type:        schema.TypeString,
 				Description: "The source network. Must be a distributed port group which is VLAN tagged.",
 				Required:    true,
+				ForceNew:    true,
 			},
 			"network_type": {
 				Type:         schema.TypeString,
 				Description:  fmt.Sprintf("The network type for the L2 extension. Allowed values include: %v.", constants.AllowedNetworkTypes),
 				Optional:     true,
 				Default:      constants.NetworkTypeDvpg,
+				ForceNew:     true,
 				ValidateFunc: validators.ValidateNetworkType,
 			},
 			"destination_t1": {
 				Type:        schema.TypeString,
 				Description: "The name of the NSX T1 at the destination.",
 				Required:    true,
+				ForceNew:    true,
 			},
 			"gateway": {
 				Type:        schema.TypeString,
 				Description: "The gateway address to configure on the NSX T1. Should be equal to the existing default gateway at the source site.",
 				Optional:    true,
-				Default:     false,
+				Computed:    true,
+				ForceNew:    true,
 			},
 			"netmask": {
 				Type:        schema.TypeString,
 				Description: "The netmask.",
 				Optional:    true,
-				Default:     false,
+				Computed:    true,
+				ForceNew:    true,
 			},
 			"mon": {
 				Type:        schema.TypeBool,
@@ -82,8 +112,15 @@ func resourceL2Extension() *schema.Resource {
 				Type:        schema.TypeString,
 				Description: "The ID of the Network Extension appliance to use for the L2 extension.",
 				Optional:    true,
-				Default:     "",
+				Computed:    true,
+			},
+			"operation_status": {
+				Type:        schema.TypeString,
+				Description: "The state of the last operation on the L2 extension.",
+				Computed:    true,
 			},
+			"pending_job_id": pendingSchema("job"),
+			"adopt_existing": adoptSchema("source network"),
 		},
 	}
 }
@@ -117,15 +154,31 @@ func resourceL2ExtensionCreate(ctx context.Context, d *schema.ResourceData, m in
 
 	serviceMeshID := d.Get("service_mesh_id").(string)
 
-	dvpg, err := GetNetworkBacking(client, sitePairing["local_endpoint_id"].(string), sourceNetwork, networkType)
+	dvpg, err := GetNetworkBacking(ctx, client, sitePairing["local_endpoint_id"].(string), sourceNetwork, networkType)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// Adopt an existing extension of the source network, if enabled. It is looked up by the name of the backing, under
+	// which the extension is created.
+	if adoptExisting(d, client) {
+		existing, err := GetL2Extensions(ctx, client, dvpg.Name)
+		switch {
+		case err == nil:
+			if err := matchL2Extension(d, existing); err != nil {
+				return diag.FromErr(err)
+			}
+			d.SetId(existing.StretchID)
+			return resourceL2ExtensionRead(ctx, d, m)
+		case !errors.Is(err, ErrNotFound):
+			return diag.FromErr(err)
+		}
+	}
+
 	applianceID := d.Get("appliance_id").(string)
 	if applianceID == "" {
 		// GET THE FIRST APPLIANCE
-		appliance, err := GetAppliance(client, sitePairing["local_endpoint_id"].(string), serviceMeshID)
+		appliance, err := GetAppliance(ctx, client, sitePairing["local_endpoint_id"].(string), serviceMeshID)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -167,27 +220,32 @@ func resourceL2ExtensionCreate(ctx context.Context, d *schema.ResourceData, m in
 		return diag.FromErr(err)
 	}
 
-	res2, err := InsertL2Extension(client, body)
+	res2, err := InsertL2Extension(ctx, client, body)
 
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Wait for job completion
-	for {
-		jr, err := GetJobResult(client, res2.ID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	_, err = WaitForJob(ctx, client, res2.ID, WaitOptions{Timeout: d.Timeout(schema.TimeoutCreate)})
+	if err != nil {
+		// The stretch ID is only known once HCX lists the extension. When the job keeps running after an
+		// interruption, the job ID stands in for it until the extension is found.
+		if interrupted(err) {
+			d.SetId(res2.ID)
 
-		if jr.IsDone {
-			break
+			lookupCtx, cancel := detachedContext(ctx)
+			defer cancel()
+
+			if res3, lookupErr := GetL2Extensions(lookupCtx, client, dvpg.Name); lookupErr == nil {
+				d.SetId(res3.StretchID)
+			}
 		}
-		time.Sleep(5 * time.Second)
+		return waitDiagnostics(d, "pending_job_id", res2.ID, err)
 	}
 
 	// Get L2 Extension ID
-	res3, err := GetL2Extensions(client, dvpg.Name)
+	res3, err := GetL2Extensions(ctx, client, dvpg.Name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -200,7 +258,78 @@ func resourceL2ExtensionCreate(ctx context.Context, d *schema.ResourceData, m in
 
 // resourceL2ExtensionRead retrieves the L2 extension configuration.
 func resourceL2ExtensionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
+	client := m.(*Client)
+
+	// Refresh the job of an interrupted apply, if any.
+	jobID := pendingID(d, "pending_job_id")
+	running, diags := refreshPending(ctx, client, d, "pending_job_id", CheckJob)
+	if running || diags.HasError() || d.Id() == "" {
+		return diags
+	}
+
+	// Replace the job ID standing in for the stretch ID once the extension exists. As in create, the extension is
+	// looked up by the name of the backing of the source network.
+	if jobID != "" && d.Id() == jobID {
+		sitePairing := d.Get("site_pairing").(map[string]interface{})
+		dvpg, err := GetNetworkBacking(ctx, client, sitePairing["local_endpoint_id"].(string),
+			d.Get("source_network").(string), d.Get("network_type").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		res, err := GetL2Extensions(ctx, client, dvpg.Name)
+		if errors.Is(err, ErrNotFound) {
+			log.Printf("[WARN] L2 extension of job %s not found, removing from state", jobID)
+			d.SetId("")
+			return diags
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(res.StretchID)
+	}
+
+	l2, err := GetL2Extension(ctx, client, d.Id())
+	if errors.Is(err, ErrNotFound) {
+		log.Printf("[WARN] L2 extension %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The source network forces a replacement, so the configured name is kept rather than the name reported by HCX,
+	// which may be normalized. It is only read on import.
+	if d.Get("source_network").(string) == "" {
+		if err := d.Set("source_network", l2.SourceNetwork.NetworkName); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("network_type", l2.SourceNetwork.NetworkType); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("destination_t1", l2.DestinationNetwork.GatewayID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("gateway", l2.Gateway); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("netmask", l2.Netmask); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("mon", l2.Features.Mon); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("egress_optimization", l2.Features.EgressOptimization); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("appliance_id", l2.SourceAppliance.ApplianceID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("operation_status", l2.OperationStatus.State); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return diags
 }
@@ -208,6 +337,44 @@ func resourceL2ExtensionRead(ctx context.Context, d *schema.ResourceData, m inte
 // resourceL2ExtensionUpdate updates the L2 extension configuration.
 func resourceL2ExtensionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
+	client := m.(*Client)
+
+	// Resume waiting for the job of an interrupted apply.
+	if jobID := pendingID(d, "pending_job_id"); jobID != "" {
+		_, err := WaitForJob(ctx, client, jobID, WaitOptions{Timeout: d.Timeout(schema.TimeoutUpdate)})
+		if err != nil {
+			return waitDiagnostics(d, "pending_job_id", jobID, err)
+		}
+
+		if err := d.Set("pending_job_id", ""); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// The features and the appliance of the extension are edited in place.
+	if d.HasChanges("mon", "egress_optimization", "appliance_id") {
+		body := UpdateL2ExtensionBody{
+			Features: Features{
+				EgressOptimization: d.Get("egress_optimization").(bool),
+				Mon:                d.Get("mon").(bool),
+			},
+			SourceAppliance: SourceAppliance{
+				ApplianceID: d.Get("appliance_id").(string),
+			},
+		}
+
+		res, err := UpdateL2Extension(ctx, client, d.Id(), body)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// Wait for job completion
+		_, err = WaitForJob(ctx, client, res.ID, WaitOptions{Timeout: d.Timeout(schema.TimeoutUpdate)})
+		if err != nil {
+			return waitDiagnostics(d, "pending_job_id", res.ID, err)
+		}
+	}
+
 	return resourceL2ExtensionRead(ctx, d, m)
 }
 
@@ -217,23 +384,32 @@ func resourceL2ExtensionDelete(ctx context.Context, d *schema.ResourceData, m in
 
 	client := m.(*Client)
 
-	res, err := DeleteL2Extension(client, d.Id())
+	res, err := DeleteL2Extension(ctx, client, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Wait for job completion
-	for {
-		jr, err := GetJobResult(client, res.ID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		if jr.IsDone {
-			break
-		}
-		time.Sleep(5 * time.Second)
+	_, err = WaitForJob(ctx, client, res.ID, WaitOptions{Timeout: d.Timeout(schema.TimeoutDelete)})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	return diags
 }
+
+// matchL2Extension returns an error if the existing L2 extension does not match the configuration.
+func matchL2Extension(d *schema.ResourceData, l2 GetL2ExtensionsResultItem) error {
+	a := adoption{kind: "L2 extension", name: l2.SourceNetwork.NetworkName}
+
+	if attrConfigured(d, "gateway") {
+		a.compare("gateway", d.Get("gateway").(string), l2.Gateway)
+	}
+	if attrConfigured(d, "netmask") {
+		a.compare("netmask", d.Get("netmask").(string), l2.Netmask)
+	}
+	a.compare("mon", d.Get("mon").(bool), l2.Features.Mon)
+	a.compare("egress_optimization", d.Get("egress_optimization").(bool), l2.Features.EgressOptimization)
+
+	return a.err()
+}
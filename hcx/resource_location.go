@@ -73,7 +73,7 @@ func resourceLocationRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 	client := m.(*Client)
 
-	resp, err := GetLocation(client)
+	resp, err := GetLocation(ctx, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -119,7 +119,7 @@ func resourceLocationUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		Province:  province,
 	}
 
-	err := SetLocation(client, body)
+	err := SetLocation(ctx, client, body)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -144,7 +144,7 @@ func resourceLocationDelete(ctx context.Context, d *schema.ResourceData, m inter
 		Province:  "",
 	}
 
-	err := SetLocation(client, body)
+	err := SetLocation(ctx, client, body)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -6,7 +6,10 @@ package hcx
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/netip"
 	"time"
 
 	"github.com/vmware/terraform-provider-hcx/hcx/constants"
@@ -14,6 +17,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // NetSchema defines the resource schema a network profile.
@@ -26,14 +30,18 @@ func NetSchema() map[string]*schema.Schema {
 			Default:     false,
 		},
 		"mtu": {
-			Type:        schema.TypeInt,
-			Description: "The MTU of the network profile.",
-			Required:    true,
+			Type:         schema.TypeInt,
+			Description:  fmt.Sprintf("The MTU of the network profile, between %d and %d.", constants.NetworkProfileMinMTU, constants.NetworkProfileMaxMTU),
+			Required:     true,
+			ValidateFunc: validation.IntBetween(constants.NetworkProfileMinMTU, constants.NetworkProfileMaxMTU),
 		},
 		"prefix_length": {
-			Type:        schema.TypeInt,
-			Description: "The prefix length for the network profile.",
-			Required:    true,
+			Type:          schema.TypeInt,
+			Description:   "The prefix length for the network profile. Required unless cidr or ip_scope is set.",
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"cidr", "ip_scope"},
+			ValidateFunc:  validation.IntBetween(0, 128),
 		},
 		"name": {
 			Type:        schema.TypeString,
@@ -41,10 +49,12 @@ func NetSchema() map[string]*schema.Schema {
 			Required:    true,
 		},
 		"gateway": {
-			Type:        schema.TypeString,
-			Description: "The gateway for the network profile.",
-			Optional:    true,
-			Default:     "",
+			Type:          schema.TypeString,
+			Description:   "The gateway for the network profile. Defaults to the first usable address of cidr, if set.",
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"ip_scope"},
+			ValidateFunc:  validators.ValidateIPAddress,
 		},
 		"site_pairing": {
 			Type:        schema.TypeMap,
@@ -52,53 +62,175 @@ func NetSchema() map[string]*schema.Schema {
 			Required:    true,
 		},
 		"primary_dns": {
-			Type:        schema.TypeString,
-			Description: "The primary DNS server for the network profile.",
-			Optional:    true,
-			Default:     "",
+			Type:          schema.TypeString,
+			Description:   "The primary DNS server for the network profile.",
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"ip_scope"},
+			ValidateFunc:  validators.ValidateIPAddress,
 		},
 		"secondary_dns": {
-			Type:        schema.TypeString,
-			Description: "The secondary DNS server for the network profile.",
-			Optional:    true,
-			Default:     "",
+			Type:          schema.TypeString,
+			Description:   "The secondary DNS server for the network profile.",
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"ip_scope"},
+			ValidateFunc:  validators.ValidateIPAddress,
 		},
 		"dns_suffix": {
-			Type:        schema.TypeString,
-			Description: "The DNS suffix for the network profile.",
-			Optional:    true,
-			Default:     "",
+			Type:          schema.TypeString,
+			Description:   "The DNS suffix for the network profile.",
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"ip_scope"},
 		},
 		"network_name": {
-			Type:        schema.TypeString,
-			Description: "The network name for the network profile.",
-			Optional:    true,
+			Type:          schema.TypeString,
+			Description:   "The network name for the network profile. Computed from the first backing, if set.",
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"backing"},
 		},
 		"network_type": {
+			Type: schema.TypeString,
+			Description: fmt.Sprintf("The network type for the network profile. Allowed values include: %v. Defaults to %s.",
+				constants.AllowedNetworkTypes, constants.NetworkTypeDvpg),
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"backing"},
+			ValidateFunc:  validators.ValidateNetworkType,
+		},
+		"backing": {
+			Type:          schema.TypeList,
+			Description:   "The network backings of the network profile, possibly across vCenter instances.",
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"network_name", "network_type"},
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"network_name": {
+						Type:        schema.TypeString,
+						Description: "The name of the network backing.",
+						Required:    true,
+					},
+					"network_type": {
+						Type: schema.TypeString,
+						Description: fmt.Sprintf("The type of the network backing. Allowed values include: %v.",
+							constants.AllowedNetworkTypes),
+						Optional:     true,
+						Default:      constants.NetworkTypeDvpg,
+						ValidateFunc: validators.ValidateNetworkType,
+					},
+					"vcenter_instance_uuid": {
+						Type:        schema.TypeString,
+						Description: "The instance UUID of the vCenter of the network backing. Defaults to the local vCenter of site_pairing.",
+						Optional:    true,
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"cidr": {
 			Type:         schema.TypeString,
-			Description:  fmt.Sprintf("The network type for the network profile. Allowed values include: %v.", constants.AllowedNetworkTypes),
+			Description:  "The CIDR block of the network profile. The prefix length, the gateway and the IP ranges are derived from it.",
+			Optional:     true,
+			ExactlyOneOf: []string{"cidr", "ip_range", "ip_scope"},
+			ValidateFunc: validation.IsCIDR,
+		},
+		"pool_offset": {
+			Type:         schema.TypeInt,
+			Description:  "The offset of the first address of the IP pool from the network address of cidr. Defaults to the address after the gateway.",
+			Optional:     true,
+			RequiredWith: []string{"cidr"},
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"pool_size": {
+			Type:         schema.TypeInt,
+			Description:  "The number of addresses of the IP pool derived from cidr. Defaults to the remaining usable addresses.",
 			Optional:     true,
-			Default:      constants.NetworkTypeDvpg,
-			ValidateFunc: validators.ValidateNetworkType,
+			RequiredWith: []string{"cidr"},
+			ValidateFunc: validation.IntAtLeast(1),
 		},
 		"ip_range": {
-			Type:     schema.TypeList,
-			Required: true,
+			Type:         schema.TypeList,
+			Description:  "The IP ranges of the network profile. Computed from cidr or from the first ip_scope, if set.",
+			Optional:     true,
+			Computed:     true,
+			ExactlyOneOf: []string{"cidr", "ip_range", "ip_scope"},
+			Elem:         ipRangeResource(),
+		},
+		"ip_scope": {
+			Type:         schema.TypeList,
+			Description:  "The IP scopes of the network profile, each with its own IPv4 or IPv6 subnet and IP pool.",
+			Optional:     true,
+			Computed:     true,
+			ExactlyOneOf: []string{"cidr", "ip_range", "ip_scope"},
 			Elem: &schema.Resource{
 				Schema: map[string]*schema.Schema{
-					"start_address": {
+					"gateway": {
+						Type:         schema.TypeString,
+						Description:  "The gateway of the IP scope.",
+						Optional:     true,
+						ValidateFunc: validators.ValidateIPAddress,
+					},
+					"prefix_length": {
+						Type:         schema.TypeInt,
+						Description:  "The prefix length of the IP scope.",
+						Required:     true,
+						ValidateFunc: validation.IntBetween(1, 128),
+					},
+					"primary_dns": {
+						Type:         schema.TypeString,
+						Description:  "The primary DNS server of the IP scope.",
+						Optional:     true,
+						ValidateFunc: validators.ValidateIPAddress,
+					},
+					"secondary_dns": {
+						Type:         schema.TypeString,
+						Description:  "The secondary DNS server of the IP scope.",
+						Optional:     true,
+						ValidateFunc: validators.ValidateIPAddress,
+					},
+					"dns_suffix": {
 						Type:        schema.TypeString,
-						Description: "The start address of the IP pool for the network profile.",
+						Description: "The DNS suffix of the IP scope.",
+						Optional:    true,
+					},
+					"ip_range": {
+						Type:        schema.TypeList,
+						Description: "The IP ranges of the IP scope.",
 						Required:    true,
+						Elem:        ipRangeResource(),
 					},
-					"end_address": {
+					"pool_id": {
 						Type:        schema.TypeString,
-						Description: "The end address of the IP pool for the network profile.",
-						Required:    true,
+						Description: "The ID of the IP pool of the IP scope.",
+						Computed:    true,
 					},
 				},
 			},
 		},
+		"adopt_existing": adoptSchema("name"),
+	}
+}
+
+// ipRangeResource defines the schema of an IP range of a network profile.
+func ipRangeResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"start_address": {
+				Type:         schema.TypeString,
+				Description:  "The start address of the IP pool for the network profile.",
+				Required:     true,
+				ValidateFunc: validators.ValidateIPAddress,
+			},
+			"end_address": {
+				Type:         schema.TypeString,
+				Description:  "The end address of the IP pool for the network profile.",
+				Required:     true,
+				ValidateFunc: validators.ValidateIPAddress,
+			},
+		},
 	}
 }
 
@@ -109,6 +241,16 @@ func resourceNetworkProfile() *schema.Resource {
 		ReadContext:   resourceNetworkProfileRead,
 		UpdateContext: resourceNetworkProfileUpdate,
 		DeleteContext: resourceNetworkProfileDelete,
+		CustomizeDiff: customizeDiffNetworkProfile,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceNetworkProfileImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(15 * time.Minute),
+		},
 
 		Schema: NetSchema(),
 	}
@@ -126,86 +268,54 @@ func resourceNetworkProfileCreate(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	mtu := d.Get("mtu").(int)
-	prefixLength := d.Get("prefix_length").(int)
-
 	name := d.Get("name").(string)
-	gateway := d.Get("gateway").(string)
-
-	primaryDNS := d.Get("primary_dns").(string)
-	secondaryDNS := d.Get("secondary_dns").(string)
-	dnsSuffix := d.Get("dns_suffix").(string)
 
-	sp := d.Get("site_pairing").(map[string]interface{})
-	vcUUID := sp["local_vc"].(string)
-	vcLocalEndpointID := sp["local_endpoint_id"].(string)
-
-	networkName, ok := d.GetOk("network_name")
-	if !ok && !vmc {
+	if _, ok := d.GetOk("network_name"); !ok && !blocksConfigured(d, "backing") {
 		return diag.Errorf("VMC switch is not enabled. Network name is mandatory")
 	}
-	networkType := d.Get("network_type").(string)
-	networkiD, err := GetNetworkBacking(client, vcLocalEndpointID, networkName.(string), networkType)
+	backings, err := networkProfileBackings(ctx, client, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	// Get IP Ranges from schema
-	ipRange := d.Get("ip_range").([]interface{})
-
-	ipr := []NetworkIPRange{}
-	for _, j := range ipRange {
-		s := j.(map[string]interface{})
-		startAddress := s["start_address"].(string)
-		endAddress := s["end_address"].(string)
-
-		ipr = append(ipr, NetworkIPRange{
-			StartAddress: startAddress,
-			EndAddress:   endAddress,
-		})
+	// Adopt an existing network profile with the same name, if enabled.
+	if adoptExisting(d, client) {
+		existing, err := GetNetworkProfile(ctx, client, name)
+		switch {
+		case err == nil:
+			if err := matchNetworkProfile(d, existing); err != nil {
+				return diag.FromErr(err)
+			}
+			d.SetId(existing.ObjectID)
+			return resourceNetworkProfileRead(ctx, d, m)
+		case !errors.Is(err, ErrNotFound):
+			return diag.FromErr(err)
+		}
 	}
 
 	body := NetworkProfileBody{
-		Name:         name,
-		Organization: constants.DefaultNetworkProfileOrg,
-		MTU:          mtu,
-		Backings: []Backing{{
-			BackingID:           networkiD.EntityID,
-			BackingName:         networkName.(string),
-			VCenterInstanceUUID: vcUUID,
-			Type:                networkType,
-		},
-		},
-		IPScopes: []IPScope{
-			{
-				DNSSuffix:       dnsSuffix,
-				Gateway:         gateway,
-				PrefixLength:    prefixLength,
-				PrimaryDNS:      primaryDNS,
-				SecondaryDNS:    secondaryDNS,
-				NetworkIPRanges: ipr,
-			},
-		},
+		Name:            name,
+		Organization:    constants.DefaultNetworkProfileOrg,
+		MTU:             mtu,
+		Backings:        backings,
+		IPScopes:        networkProfileIPScopes(d, nil),
 		L3TenantManaged: false,
 		OwnedBySystem:   true,
 	}
 
-	res, err := InsertNetworkProfile(client, body)
+	res, err := InsertNetworkProfile(ctx, client, body)
 
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	// Wait for job completion
-	for {
-		jr, err := GetJobResult(client, res.Data.JobID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	// Track the network profile before waiting, so that an interrupted creation leaves a tainted resource.
+	d.SetId(res.Data.ObjectID)
 
-		if jr.IsDone {
-			break
-		}
-		time.Sleep(5 * time.Second)
+	// Wait for job completion
+	_, err = WaitForJob(ctx, client, res.Data.JobID, WaitOptions{Timeout: d.Timeout(schema.TimeoutCreate)})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	return resourceNetworkProfileRead(ctx, d, m)
@@ -216,13 +326,92 @@ func resourceNetworkProfileRead(ctx context.Context, d *schema.ResourceData, m i
 	var diags diag.Diagnostics
 
 	client := m.(*Client)
-	name := d.Get("name").(string)
 
-	np, err := GetNetworkProfile(client, name)
+	// A VMC network profile is not created, so it is resolved by name until its ID is known.
+	if d.Id() == "" {
+		np, err := GetNetworkProfile(ctx, client, d.Get("name").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(np.ObjectID)
+	}
+
+	np, err := GetNetworkProfileByID(ctx, client, d.Id())
+	if errors.Is(err, ErrNotFound) {
+		log.Printf("[WARN] Network profile %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	d.SetId(np.ObjectID)
+
+	if err := d.Set("name", np.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("mtu", np.MTU); err != nil {
+		return diag.FromErr(err)
+	}
+
+	backings := []map[string]interface{}{}
+	for _, b := range np.Backings {
+		backings = append(backings, map[string]interface{}{
+			"network_name":          b.BackingName,
+			"network_type":          b.Type,
+			"vcenter_instance_uuid": b.VCenterInstanceUUID,
+		})
+	}
+	if err := d.Set("backing", backings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(np.Backings) > 0 {
+		if err := d.Set("network_name", np.Backings[0].BackingName); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("network_type", np.Backings[0].Type); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if len(np.IPScopes) > 0 {
+		scope := np.IPScopes[0]
+		if err := d.Set("prefix_length", scope.PrefixLength); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("gateway", scope.Gateway); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("primary_dns", scope.PrimaryDNS); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("secondary_dns", scope.SecondaryDNS); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("dns_suffix", scope.DNSSuffix); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := d.Set("ip_range", flattenNetworkIPRanges(scope.NetworkIPRanges)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	scopes := []map[string]interface{}{}
+	for _, scope := range np.IPScopes {
+		scopes = append(scopes, map[string]interface{}{
+			"gateway":       scope.Gateway,
+			"prefix_length": scope.PrefixLength,
+			"primary_dns":   scope.PrimaryDNS,
+			"secondary_dns": scope.SecondaryDNS,
+			"dns_suffix":    scope.DNSSuffix,
+			"ip_range":      flattenNetworkIPRanges(scope.NetworkIPRanges),
+			"pool_id":       scope.PoolID,
+		})
+	}
+	if err := d.Set("ip_scope", scopes); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return diags
 }
@@ -235,38 +424,16 @@ func resourceNetworkProfileUpdate(ctx context.Context, d *schema.ResourceData, m
 	// Get values from schema
 	vmc := d.Get("vmc").(bool)
 	mtu := d.Get("mtu").(int)
-	prefixLength := d.Get("prefix_length").(int)
-
 	name := d.Get("name").(string)
-	gateway := d.Get("gateway").(string)
-
-	primaryDNS := d.Get("primary_dns").(string)
-	secondaryDNS := d.Get("secondary_dns").(string)
-	dnsSuffix := d.Get("dns_suffix").(string)
-	networkName := d.Get("network_name").(string)
-	networkType := d.Get("network_type").(string)
-
-	sp := d.Get("site_pairing").(map[string]interface{})
-	vcUUID := sp["local_vc"].(string)
-	vcLocalEndpointID := sp["local_endpoint_id"].(string)
 
-	// Get IP Ranges from schema
-	ipRange := d.Get("ip_range").([]interface{})
-
-	ipr := []NetworkIPRange{}
-	for _, j := range ipRange {
-		s := j.(map[string]interface{})
-		startAddress := s["start_address"].(string)
-		endAddress := s["end_address"].(string)
-
-		ipr = append(ipr, NetworkIPRange{
-			StartAddress: startAddress,
-			EndAddress:   endAddress,
-		})
+	// Read the existing profile, by name when a VMC network profile is not tracked yet.
+	var body NetworkProfileBody
+	var err error
+	if d.Id() == "" {
+		body, err = GetNetworkProfile(ctx, client, name)
+	} else {
+		body, err = GetNetworkProfileByID(ctx, client, d.Id())
 	}
-
-	// Read the existing profile
-	body, err := GetNetworkProfile(client, name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -276,51 +443,27 @@ func resourceNetworkProfileUpdate(ctx context.Context, d *schema.ResourceData, m
 	if !vmc {
 		body.Name = name
 
-		// Get network details
-		networkID, err := GetNetworkBacking(client, vcLocalEndpointID, networkName, networkType)
+		body.Backings, err = networkProfileBackings(ctx, client, d)
 		if err != nil {
 			return diag.FromErr(err)
 		}
-
-		body.Backings = []Backing{{
-			BackingID:           networkID.EntityID,
-			BackingName:         networkName,
-			VCenterInstanceUUID: vcUUID,
-			Type:                networkType,
-		}}
 	}
 
 	body.MTU = mtu
 
-	body.IPScopes = []IPScope{
-		{
-			DNSSuffix:       dnsSuffix,
-			Gateway:         gateway,
-			PrefixLength:    prefixLength,
-			PrimaryDNS:      primaryDNS,
-			SecondaryDNS:    secondaryDNS,
-			NetworkIPRanges: ipr,
-			PoolID:          body.IPScopes[0].PoolID,
-		},
-	}
+	// Keep the pool of each existing IP scope, so that the addresses allocated from it are not churned.
+	body.IPScopes = networkProfileIPScopes(d, body.IPScopes)
 
-	res, err := UpdateNetworkProfile(client, body)
+	res, err := UpdateNetworkProfile(ctx, client, body)
 
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Wait for job completion
-	for {
-		jr, err := GetJobResult(client, res.Data.JobID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		if jr.IsDone {
-			break
-		}
-		time.Sleep(5 * time.Second)
+	_, err = WaitForJob(ctx, client, res.Data.JobID, WaitOptions{Timeout: d.Timeout(schema.TimeoutUpdate)})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	return resourceNetworkProfileRead(ctx, d, m)
@@ -341,7 +484,7 @@ func resourceNetworkProfileDelete(ctx context.Context, d *schema.ResourceData, m
 		// If VMware Cloud on AWS, don't really delete the network profile
 		// Read the existing profile
 		/*
-			body, err := hcx.GetNetworkProfile(client, name)
+			body, err := hcx.GetNetworkProfile(ctx, client, name)
 			if err != nil {
 				return diag.FromErr(err)
 			}
@@ -349,7 +492,7 @@ func resourceNetworkProfileDelete(ctx context.Context, d *schema.ResourceData, m
 			// Empty the IP Ranges
 			body.IPScopes[0].NetworkIPRanges = []hcx.NetworkIPRange{}
 
-			res, err = hcx.UpdateNetworkProfile(client, body)
+			res, err = hcx.UpdateNetworkProfile(ctx, client, body)
 
 			if err != nil {
 				return diag.FromErr(err)
@@ -357,24 +500,430 @@ func resourceNetworkProfileDelete(ctx context.Context, d *schema.ResourceData, m
 		*/
 		return diags
 	}
-	res, err = DeleteNetworkProfile(client, d.Id())
+	res, err = DeleteNetworkProfile(ctx, client, d.Id())
 
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Wait for job completion
-	for {
-		jr, err := GetJobResult(client, res.Data.JobID)
+	_, err = WaitForJob(ctx, client, res.Data.JobID, WaitOptions{Timeout: d.Timeout(schema.TimeoutDelete)})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// customizeDiffNetworkProfile computes the top-level backing and IP scope attributes of the network profile from the
+// backing and ip_scope blocks, or the other way around, and validates the IP ranges of each IP scope. Each step is
+// skipped while any of its values is unknown.
+func customizeDiffNetworkProfile(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := customizeDiffNetworkProfileBackings(d); err != nil {
+		return err
+	}
+
+	config := d.GetRawConfig()
+	scopes := config.GetAttr("ip_scope")
+	if !scopes.IsKnown() {
+		return nil
+	}
+	if !scopes.IsNull() && scopes.LengthInt() > 0 {
+		return customizeDiffNetworkProfileIPScopes(d)
+	}
+
+	// The DNS settings are only computed from the IP scopes, so they are cleared when they are not configured.
+	for _, key := range []string{"primary_dns", "secondary_dns", "dns_suffix"} {
+		if config.GetAttr(key).IsNull() {
+			if err := d.SetNew(key, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := customizeDiffNetworkProfileIPRanges(d); err != nil {
+		return err
+	}
+
+	if d.Id() != "" && d.HasChanges("prefix_length", "gateway", "primary_dns", "secondary_dns", "dns_suffix", "ip_range") {
+		return d.SetNewComputed("ip_scope")
+	}
+
+	return nil
+}
+
+// customizeDiffNetworkProfileBackings computes network_name and network_type from the first backing block, if set.
+// Otherwise, network_type defaults to dvpg and the backings are recomputed when the network changes.
+func customizeDiffNetworkProfileBackings(d *schema.ResourceDiff) error {
+	config := d.GetRawConfig()
+	backings := config.GetAttr("backing")
+	if !backings.IsKnown() {
+		return nil
+	}
+
+	if backings.IsNull() || backings.LengthInt() == 0 {
+		// A VMC network profile configures no network, so its type is read from HCX.
+		if config.GetAttr("network_type").IsNull() && !config.GetAttr("network_name").IsNull() {
+			if err := d.SetNew("network_type", constants.NetworkTypeDvpg); err != nil {
+				return err
+			}
+		}
+		if d.Id() != "" && d.HasChanges("network_name", "network_type") {
+			return d.SetNewComputed("backing")
+		}
+		return nil
+	}
+
+	for _, key := range []string{"network_name", "network_type"} {
+		if err := setNewFrom(d, key, "backing.0."+key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// customizeDiffNetworkProfileIPScopes computes the top-level IP scope attributes from the first ip_scope block, and
+// validates the IP ranges of each ip_scope block against its gateway and prefix length.
+func customizeDiffNetworkProfileIPScopes(d *schema.ResourceDiff) error {
+	for _, key := range []string{"gateway", "prefix_length", "primary_dns", "secondary_dns", "dns_suffix", "ip_range"} {
+		if err := setNewFrom(d, key, "ip_scope.0."+key); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for i := range d.Get("ip_scope").([]interface{}) {
+		path := fmt.Sprintf("ip_scope.%d.", i)
+		if !d.NewValueKnown(path+"gateway") || !d.NewValueKnown(path+"prefix_length") || !d.NewValueKnown(path+"ip_range") {
+			continue
+		}
+
+		ranges := []validators.IPRange{}
+		for j, k := range d.Get(path + "ip_range").([]interface{}) {
+			r, ok := k.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ranges = append(ranges, validators.IPRange{
+				Path:         fmt.Sprintf("%sip_range.%d", path, j),
+				StartAddress: r["start_address"].(string),
+				EndAddress:   r["end_address"].(string),
+			})
+		}
+
+		errs = append(errs, validators.ValidateIPRanges(path, d.Get(path+"gateway").(string), d.Get(path+"prefix_length").(int), ranges, false))
+	}
+
+	return errors.Join(errs...)
+}
+
+// customizeDiffNetworkProfileIPRanges derives the prefix length, the gateway and the IP ranges of the network profile
+// from cidr, when set. Otherwise, it validates the IP ranges against the gateway and the prefix length.
+func customizeDiffNetworkProfileIPRanges(d *schema.ResourceDiff) error {
+	config := d.GetRawConfig()
+	if !config.GetAttr("gateway").IsKnown() {
+		return nil
+	}
+	gateway := ""
+	if v := config.GetAttr("gateway"); !v.IsNull() {
+		gateway = v.AsString()
+	}
+
+	if cidr := config.GetAttr("cidr"); !cidr.IsNull() {
+		if !cidr.IsKnown() || !d.NewValueKnown("pool_offset") || !d.NewValueKnown("pool_size") {
+			return nil
+		}
+
+		scope, err := cidrIPScope(cidr.AsString(), gateway, d.Get("pool_offset").(int), d.Get("pool_size").(int))
 		if err != nil {
-			return diag.FromErr(err)
+			return fmt.Errorf("%q: %w", "cidr", err)
+		}
+
+		ipRanges := []map[string]interface{}{}
+		for _, r := range scope.NetworkIPRanges {
+			ipRanges = append(ipRanges, map[string]interface{}{
+				"start_address": r.StartAddress,
+				"end_address":   r.EndAddress,
+			})
 		}
 
-		if jr.IsDone {
-			break
+		if err := d.SetNew("prefix_length", scope.PrefixLength); err != nil {
+			return err
 		}
-		time.Sleep(5 * time.Second)
+		if err := d.SetNew("gateway", scope.Gateway); err != nil {
+			return err
+		}
+		return d.SetNew("ip_range", ipRanges)
 	}
 
-	return diags
+	if config.GetAttr("prefix_length").IsNull() {
+		return fmt.Errorf("%q is required when %q is not set", "prefix_length", "cidr")
+	}
+
+	// The gateway is only computed from cidr, so it is cleared when it is not configured.
+	if config.GetAttr("gateway").IsNull() {
+		if err := d.SetNew("gateway", ""); err != nil {
+			return err
+		}
+	}
+
+	if !d.NewValueKnown("prefix_length") || !d.NewValueKnown("ip_range") {
+		return nil
+	}
+
+	ranges := []validators.IPRange{}
+	for i, j := range d.Get("ip_range").([]interface{}) {
+		r, ok := j.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ranges = append(ranges, validators.IPRange{
+			Path:         fmt.Sprintf("ip_range.%d", i),
+			StartAddress: r["start_address"].(string),
+			EndAddress:   r["end_address"].(string),
+		})
+	}
+
+	// A VMC network profile updates the IP ranges of an existing profile, which may not define a subnet.
+	return validators.ValidateIPRanges("", gateway, d.Get("prefix_length").(int), ranges, d.Get("vmc").(bool))
+}
+
+// setNewFrom sets the new value of key to the new value of from, or marks it as computed while from is unknown.
+func setNewFrom(d *schema.ResourceDiff, key, from string) error {
+	if !d.NewValueKnown(from) {
+		return d.SetNewComputed(key)
+	}
+
+	return d.SetNew(key, d.Get(from))
+}
+
+// resourceNetworkProfileImport imports a network profile by its object ID or its name.
+func resourceNetworkProfileImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
+
+	profiles, err := GetNetworkProfiles(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, np := range profiles {
+		if np.ObjectID == d.Id() || np.Name == d.Id() {
+			d.SetId(np.ObjectID)
+			if err := d.Set("vmc", false); err != nil {
+				return nil, err
+			}
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("network profile %s: %w", d.Id(), ErrNotFound)
+}
+
+// matchNetworkProfile returns an error if the existing network profile does not match the configuration.
+func matchNetworkProfile(d *schema.ResourceData, np NetworkProfileBody) error {
+	a := adoption{kind: "network profile", name: np.Name}
+
+	a.compare("mtu", d.Get("mtu").(int), np.MTU)
+
+	configuredBackings := []string{}
+	for _, b := range networkProfileBackingConfigs(d) {
+		configuredBackings = append(configuredBackings, b["network_name"].(string))
+	}
+	backings := []string{}
+	for _, b := range np.Backings {
+		backings = append(backings, b.BackingName)
+	}
+	a.compareSet("network_name", configuredBackings, backings)
+
+	if len(np.IPScopes) == 0 {
+		a.mismatches = append(a.mismatches, "no IP scope is defined in HCX")
+		return a.err()
+	}
+
+	configuredScopes, configuredRanges := []string{}, []string{}
+	for _, scope := range networkProfileIPScopes(d, nil) {
+		configuredScopes = append(configuredScopes, fmt.Sprintf("%s/%d", scope.Gateway, scope.PrefixLength))
+		for _, r := range scope.NetworkIPRanges {
+			configuredRanges = append(configuredRanges, fmt.Sprintf("%s-%s", r.StartAddress, r.EndAddress))
+		}
+	}
+
+	// A network profile configured with the top-level attributes only defines its first IP scope.
+	existing := np.IPScopes
+	if !blocksConfigured(d, "ip_scope") {
+		existing = existing[:1]
+	}
+	scopes, ranges := []string{}, []string{}
+	for _, scope := range existing {
+		scopes = append(scopes, fmt.Sprintf("%s/%d", scope.Gateway, scope.PrefixLength))
+		for _, r := range scope.NetworkIPRanges {
+			ranges = append(ranges, fmt.Sprintf("%s-%s", r.StartAddress, r.EndAddress))
+		}
+	}
+	a.compareSet("ip_scope", configuredScopes, scopes)
+	a.compareSet("ip_range", configuredRanges, ranges)
+
+	return a.err()
+}
+
+// blocksConfigured reports whether any block of key is set in the configuration.
+func blocksConfigured(d *schema.ResourceData, key string) bool {
+	v := d.GetRawConfig().GetAttr(key)
+	return v.IsKnown() && !v.IsNull() && v.LengthInt() > 0
+}
+
+// networkProfileBackingConfigs returns the configured backings of the network profile, from the backing blocks if
+// set, or from network_name and network_type otherwise. The vCenter instance UUID is empty unless configured.
+func networkProfileBackingConfigs(d *schema.ResourceData) []map[string]interface{} {
+	if !blocksConfigured(d, "backing") {
+		networkType := d.Get("network_type").(string)
+		if networkType == "" {
+			networkType = constants.NetworkTypeDvpg
+		}
+		return []map[string]interface{}{{
+			"network_name":          d.Get("network_name").(string),
+			"network_type":          networkType,
+			"vcenter_instance_uuid": "",
+		}}
+	}
+
+	configs := []map[string]interface{}{}
+	for i, v := range d.GetRawConfig().GetAttr("backing").AsValueSlice() {
+		b := d.Get(fmt.Sprintf("backing.%d", i)).(map[string]interface{})
+
+		// The computed vCenter instance UUID of the state may belong to another backing, so it is read from the
+		// configuration.
+		b["vcenter_instance_uuid"] = ""
+		if vc := v.GetAttr("vcenter_instance_uuid"); vc.IsKnown() && !vc.IsNull() {
+			b["vcenter_instance_uuid"] = vc.AsString()
+		}
+		configs = append(configs, b)
+	}
+
+	return configs
+}
+
+// networkProfileBackings returns the backings of the network profile. A backing belongs to the local vCenter of the
+// site pairing, unless its vCenter instance UUID is configured.
+func networkProfileBackings(ctx context.Context, client *Client, d *schema.ResourceData) ([]Backing, error) {
+	sp := d.Get("site_pairing").(map[string]interface{})
+	vcUUID := sp["local_vc"].(string)
+	vcLocalEndpointID := sp["local_endpoint_id"].(string)
+
+	backings := []Backing{}
+	for _, b := range networkProfileBackingConfigs(d) {
+		networkName := b["network_name"].(string)
+		networkType := b["network_type"].(string)
+		vCenterInstanceUUID := b["vcenter_instance_uuid"].(string)
+		if vCenterInstanceUUID == "" {
+			vCenterInstanceUUID = vcUUID
+		}
+
+		// The network is looked up in the inventory of its own vCenter, since port groups of different vCenters may
+		// share a name.
+		network, err := GetVcNetworkBacking(ctx, client, vcLocalEndpointID, vCenterInstanceUUID, networkName, networkType)
+		if err != nil {
+			return nil, err
+		}
+
+		backings = append(backings, Backing{
+			BackingID:           network.EntityID,
+			BackingName:         networkName,
+			VCenterInstanceUUID: vCenterInstanceUUID,
+			Type:                networkType,
+		})
+	}
+
+	return backings, nil
+}
+
+// networkProfileIPScopes returns the IP scopes of the network profile, from the ip_scope blocks if set, or from the
+// top-level attributes otherwise. Each IP scope keeps the pool of the existing IP scope of the same subnet. A single
+// IP scope keeps the pool of a single existing IP scope, even when its subnet changes.
+func networkProfileIPScopes(d *schema.ResourceData, existing []IPScope) []IPScope {
+	configs := []interface{}{map[string]interface{}{
+		"gateway":       d.Get("gateway"),
+		"prefix_length": d.Get("prefix_length"),
+		"primary_dns":   d.Get("primary_dns"),
+		"secondary_dns": d.Get("secondary_dns"),
+		"dns_suffix":    d.Get("dns_suffix"),
+		"ip_range":      d.Get("ip_range"),
+	}}
+	if blocksConfigured(d, "ip_scope") {
+		configs = d.Get("ip_scope").([]interface{})
+	}
+
+	scopes := []IPScope{}
+	used := map[int]bool{}
+	for _, j := range configs {
+		s := j.(map[string]interface{})
+		scope := IPScope{
+			DNSSuffix:       s["dns_suffix"].(string),
+			Gateway:         s["gateway"].(string),
+			PrefixLength:    s["prefix_length"].(int),
+			PrimaryDNS:      s["primary_dns"].(string),
+			SecondaryDNS:    s["secondary_dns"].(string),
+			NetworkIPRanges: expandNetworkIPRanges(s["ip_range"].([]interface{})),
+		}
+
+		subnet := ipScopeSubnet(scope.Gateway, scope.PrefixLength)
+		for i, e := range existing {
+			if !used[i] && ipScopeSubnet(e.Gateway, e.PrefixLength) == subnet {
+				scope.PoolID = e.PoolID
+				used[i] = true
+				break
+			}
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	if len(scopes) == 1 && len(existing) == 1 {
+		scopes[0].PoolID = existing[0].PoolID
+	}
+
+	return scopes
+}
+
+// ipScopeSubnet returns the subnet of an IP scope, or its gateway if the IP scope does not define a valid subnet.
+func ipScopeSubnet(gateway string, prefixLength int) string {
+	gw, err := netip.ParseAddr(gateway)
+	if err != nil {
+		return gateway
+	}
+
+	subnet, err := gw.Prefix(prefixLength)
+	if err != nil {
+		return gateway
+	}
+
+	return subnet.String()
+}
+
+// expandNetworkIPRanges converts the ip_range blocks of the schema to network IP ranges.
+func expandNetworkIPRanges(ipRange []interface{}) []NetworkIPRange {
+	ipr := []NetworkIPRange{}
+	for _, j := range ipRange {
+		s := j.(map[string]interface{})
+		ipr = append(ipr, NetworkIPRange{
+			StartAddress: s["start_address"].(string),
+			EndAddress:   s["end_address"].(string),
+		})
+	}
+
+	return ipr
+}
+
+// flattenNetworkIPRanges converts network IP ranges to ip_range blocks of the schema.
+func flattenNetworkIPRanges(ipr []NetworkIPRange) []map[string]interface{} {
+	ipRange := []map[string]interface{}{}
+	for _, r := range ipr {
+		ipRange = append(ipRange, map[string]interface{}{
+			"start_address": r.StartAddress,
+			"end_address":   r.EndAddress,
+		})
+	}
+
+	return ipRange
 }
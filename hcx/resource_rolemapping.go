@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"context"
+	"log"
 
 	"github.com/vmware/terraform-provider-hcx/hcx/constants"
 
@@ -20,6 +21,9 @@ func resourceRoleMapping() *schema.Resource {
 		ReadContext:   resourceRoleMappingRead,
 		UpdateContext: resourceRoleMappingUpdate,
 		DeleteContext: resourceRoleMappingDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRoleMappingImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"admin": {
@@ -71,6 +75,34 @@ func resourceRoleMappingCreate(ctx context.Context, d *schema.ResourceData, m in
 func resourceRoleMappingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
+	client := m.(*Client)
+
+	res, err := GetRoleMapping(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	groups := map[string][]map[string]string{}
+	for _, j := range res {
+		for _, g := range j.UserGroups {
+			groups[j.Role] = append(groups[j.Role], map[string]string{"user_group": g})
+		}
+	}
+
+	// The role mapping is removed by clearing the groups of both roles.
+	if len(groups[constants.RoleSystemAdmin]) == 0 && len(groups[constants.RoleEnterpriseAdmin]) == 0 {
+		log.Printf("[WARN] Role mapping not found, removing from state")
+		d.SetId("")
+		return diags
+	}
+
+	if err := d.Set("admin", groups[constants.RoleSystemAdmin]); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("enterprise", groups[constants.RoleEnterpriseAdmin]); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return diags
 }
 
@@ -109,7 +141,7 @@ func resourceRoleMappingUpdate(ctx context.Context, d *schema.ResourceData, m in
 		json.NewEncoder(buf).Encode(body)
 		return diag.Errorf("%s", buf)
 	*/
-	_, err := PutRoleMapping(client, body)
+	_, err := PutRoleMapping(ctx, client, body)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -135,10 +167,18 @@ func resourceRoleMappingDelete(ctx context.Context, d *schema.ResourceData, m in
 		},
 	}
 
-	_, err := PutRoleMapping(client, body)
+	_, err := PutRoleMapping(ctx, client, body)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	return diags
 }
+
+// resourceRoleMappingImport imports the role mapping of the HCX Manager. There is a single role mapping, so the ID
+// given on import is ignored.
+func resourceRoleMappingImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.SetId("role_mapping")
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -5,15 +5,14 @@
 package hcx
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
-	"github.com/vmware/terraform-provider-hcx/hcx/constants"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -24,22 +23,44 @@ func resourceServiceMesh() *schema.Resource {
 		ReadContext:   resourceServiceMeshRead,
 		UpdateContext: resourceServiceMeshUpdate,
 		DeleteContext: resourceServiceMeshDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffPending("pending_task_id"),
+			// A service mesh cannot move to another site pairing. The site pairing is not imported, so only a change
+			// of the paired site forces a new service mesh.
+			customdiff.ForceNewIfChange("site_pairing", func(ctx context.Context, old, new, meta interface{}) bool {
+				oldID, _ := old.(map[string]interface{})["id"].(string)
+				newID, _ := new.(map[string]interface{})["id"].(string)
+				return oldID != "" && oldID != newID
+			}),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServiceMeshImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(90 * time.Minute),
+			Update: schema.DefaultTimeout(90 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
 				Description: "The name of the service mesh.",
 				Required:    true,
+				ForceNew:    true,
 			},
 			"local_compute_profile": {
 				Type:        schema.TypeString,
 				Description: "The local compute profile name.",
 				Required:    true,
+				ForceNew:    true,
 			},
 			"remote_compute_profile": {
 				Type:        schema.TypeString,
 				Description: "The remote compute profile name.",
 				Required:    true,
+				ForceNew:    true,
 			},
 			"app_path_resiliency_enabled": {
 				Type:        schema.TypeBool,
@@ -104,6 +125,8 @@ func resourceServiceMesh() *schema.Resource {
 					},
 				},
 			},
+			"pending_task_id": pendingSchema("task"),
+			"adopt_existing":  adoptSchema("name"),
 		},
 	}
 }
@@ -113,6 +136,163 @@ func resourceServiceMeshCreate(ctx context.Context, d *schema.ResourceData, m in
 
 	client := m.(*Client)
 
+	name := d.Get("name").(string)
+
+	// Adopt an existing service mesh with the same name, if enabled.
+	if adoptExisting(d, client) {
+		existing, err := GetServiceMeshByName(ctx, client, name)
+		switch {
+		case err == nil:
+			if err := matchServiceMesh(d, existing); err != nil {
+				return diag.FromErr(err)
+			}
+			d.SetId(existing.ServiceMeshID)
+			return resourceServiceMeshRead(ctx, d, m)
+		case !errors.Is(err, ErrNotFound):
+			return diag.FromErr(err)
+		}
+	}
+
+	body, err := serviceMeshBody(ctx, client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res2, err := InsertServiceMesh(ctx, client, body)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Save the service mesh as soon as it exists, so that an interrupted apply can resume waiting for the task.
+	d.SetId(res2.Data.ServiceMeshID)
+
+	// Wait for task completion
+	_, err = WaitForTask(ctx, client, res2.Data.InterconnectID, WaitOptions{Timeout: d.Timeout(schema.TimeoutCreate)})
+	if err != nil {
+		return waitDiagnostics(d, "pending_task_id", res2.Data.InterconnectID, err)
+	}
+
+	return resourceServiceMeshRead(ctx, d, m)
+
+}
+
+// resourceServiceMeshRead retrieves the service mesh configuration.
+func resourceServiceMeshRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	// Refresh the task of an interrupted apply, if any.
+	running, diags := refreshPending(ctx, client, d, "pending_task_id", CheckTask)
+	if running || diags.HasError() || d.Id() == "" {
+		return diags
+	}
+
+	sm, err := GetServiceMesh(ctx, client, d.Id())
+	if errors.Is(err, ErrNotFound) {
+		log.Printf("[WARN] Service mesh %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("name", sm.Name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	local, remote := serviceMeshComputeProfiles(d, sm)
+	if err := d.Set("local_compute_profile", local.ComputeProfileName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("remote_compute_profile", remote.ComputeProfileName); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setServices(d, sm.Services); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("uplink_max_bandwidth", sm.WanoptConfig.UplinkMaxBandwidth); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("app_path_resiliency_enabled", sm.TrafficEnggCfg.IsAppPathResiliencyEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("tcp_flow_conditioning_enabled", sm.TrafficEnggCfg.IsTCPFlowConditioningEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(sm.SwitchPairCount) > 0 {
+		if err := d.Set("nb_appliances", sm.SwitchPairCount[0].L2cApplianceCount); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return append(diags, setServiceMeshAppliances(ctx, client, d, local.EndpointID)...)
+}
+
+// resourceServiceMeshUpdate updates the service mesh configuration.
+func resourceServiceMeshUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	client := m.(*Client)
+
+	// Resume waiting for the task of an interrupted apply.
+	if taskID := pendingID(d, "pending_task_id"); taskID != "" {
+		_, err := WaitForTask(ctx, client, taskID, WaitOptions{Timeout: d.Timeout(schema.TimeoutUpdate)})
+		if err != nil {
+			return waitDiagnostics(d, "pending_task_id", taskID, err)
+		}
+
+		if err := d.Set("pending_task_id", ""); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges("service", "uplink_max_bandwidth", "app_path_resiliency_enabled", "tcp_flow_conditioning_enabled", "nb_appliances") {
+		body, err := serviceMeshBody(ctx, client, d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		res, err := UpdateServiceMesh(ctx, client, d.Id(), body)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// Wait for task completion
+		_, err = WaitForTask(ctx, client, res.Data.InterconnectID, WaitOptions{Timeout: d.Timeout(schema.TimeoutUpdate)})
+		if err != nil {
+			return waitDiagnostics(d, "pending_task_id", res.Data.InterconnectID, err)
+		}
+	}
+
+	return resourceServiceMeshRead(ctx, d, m)
+}
+
+// resourceServiceMeshDelete removes the service mesh configuration and clears the state of the resource in the schema.
+func resourceServiceMeshDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(*Client)
+	force := d.Get("force_delete").(bool)
+
+	res, err := DeleteServiceMesh(ctx, client, d.Id(), force)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Wait for task completion
+	_, err = WaitForTask(ctx, client, res.Data.InterconnectTaskID, WaitOptions{Timeout: d.Timeout(schema.TimeoutDelete)})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// serviceMeshBody builds the body used to create or edit the service mesh from the resource configuration.
+func serviceMeshBody(ctx context.Context, client *Client, d *schema.ResourceData) (InsertServiceMeshBody, error) {
 	name := d.Get("name").(string)
 	sitePairing := d.Get("site_pairing").(map[string]interface{})
 	localEndpointID := sitePairing["local_endpoint_id"].(string)
@@ -138,15 +318,15 @@ func resourceServiceMeshCreate(ctx context.Context, d *schema.ResourceData, m in
 	}
 
 	remoteComputeProfileName := d.Get("remote_compute_profile").(string)
-	remoteComputeProfile, err := GetComputeProfile(client, remoteEndpointID, remoteComputeProfileName)
+	remoteComputeProfile, err := GetComputeProfile(ctx, client, remoteEndpointID, remoteComputeProfileName)
 	if err != nil {
-		return diag.FromErr(err)
+		return InsertServiceMeshBody{}, err
 	}
 
 	localComputeProfileName := d.Get("local_compute_profile").(string)
-	localComputeProfile, err := GetComputeProfile(client, localEndpointID, localComputeProfileName)
+	localComputeProfile, err := GetComputeProfile(ctx, client, localEndpointID, localComputeProfileName)
 	if err != nil {
-		return diag.FromErr(err)
+		return InsertServiceMeshBody{}, err
 	}
 
 	nbAppliances := d.Get("nb_appliances").(int)
@@ -186,37 +366,59 @@ func resourceServiceMeshCreate(ctx context.Context, d *schema.ResourceData, m in
 		},
 	}
 
-	buf := new(bytes.Buffer)
-	if err := json.NewEncoder(buf).Encode(body); err != nil {
-		return diag.FromErr(err)
-	}
+	return body, nil
+}
 
-	res2, err := InsertServiceMesh(client, body)
+// resourceServiceMeshImport imports a service mesh by ID or by name.
+func resourceServiceMeshImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
 
+	meshes, err := GetServiceMeshes(ctx, client)
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
 	}
 
-	// Wait for task completion
-	for {
-		jr, err := GetTaskResult(client, res2.Data.InterconnectID)
-		if err != nil {
-			return diag.FromErr(err)
+	for _, sm := range meshes {
+		if sm.ServiceMeshID == d.Id() || sm.Name == d.Id() {
+			d.SetId(sm.ServiceMeshID)
+			if err := d.Set("force_delete", false); err != nil {
+				return nil, err
+			}
+			return []*schema.ResourceData{d}, nil
 		}
+	}
 
-		if jr.Status == constants.SuccessStatus {
-			break
-		}
+	return nil, fmt.Errorf("service mesh %s: %w", d.Id(), ErrNotFound)
+}
 
-		if jr.Status == constants.FailedStatus {
-			return diag.FromErr(errors.New("task failed"))
+// serviceMeshComputeProfiles returns the local and remote compute profiles of the service mesh. The local profile is
+// the one of the local endpoint of the site pairing when known, and otherwise the first one, as sent on create.
+func serviceMeshComputeProfiles(d *schema.ResourceData, sm ServiceMesh) (local, remote ComputeProfile) {
+	localEndpointID, _ := d.Get("site_pairing").(map[string]interface{})["local_endpoint_id"].(string)
+
+	localIndex := 0
+	for i, cp := range sm.ComputeProfiles {
+		if localEndpointID != "" && cp.EndpointID == localEndpointID {
+			localIndex = i
 		}
+	}
 
-		time.Sleep(5 * time.Second)
+	for i, cp := range sm.ComputeProfiles {
+		if i == localIndex {
+			local = cp
+		} else {
+			remote = cp
+		}
 	}
 
+	return local, remote
+}
+
+// setServiceMeshAppliances sets the IDs of the Network Extension appliances deployed by the service mesh on the local
+// endpoint.
+func setServiceMeshAppliances(ctx context.Context, client *Client, d *schema.ResourceData, endpointID string) diag.Diagnostics {
 	// Update Appliances ID
-	appliances, err := GetAppliances(client, sitePairing["local_endpoint_id"].(string), res2.Data.ServiceMeshID)
+	appliances, err := GetAppliances(ctx, client, endpointID, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -232,54 +434,33 @@ func resourceServiceMeshCreate(ctx context.Context, d *schema.ResourceData, m in
 		return diag.FromErr(err)
 	}
 
-	d.SetId(res2.Data.ServiceMeshID)
-
-	return resourceServiceMeshRead(ctx, d, m)
-
-}
-
-// resourceServiceMeshRead retrieves the service mesh configuration.
-func resourceServiceMeshRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
-
-	return diags
-}
-
-// resourceServiceMeshUpdate updates the service mesh configuration.
-func resourceServiceMeshUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-
-	return resourceServiceMeshRead(ctx, d, m)
+	return nil
 }
 
-// resourceServiceMeshDelete removes the service mesh configuration and clears the state of the resource in the schema.
-func resourceServiceMeshDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
-
-	client := m.(*Client)
-	force := d.Get("force_delete").(bool)
+// matchServiceMesh returns an error if the existing service mesh does not match the configuration.
+func matchServiceMesh(d *schema.ResourceData, sm ServiceMesh) error {
+	a := adoption{kind: "service mesh", name: sm.Name}
 
-	res, err := DeleteServiceMesh(client, d.Id(), force)
-	if err != nil {
-		return diag.FromErr(err)
+	profiles := []string{}
+	for _, cp := range sm.ComputeProfiles {
+		profiles = append(profiles, cp.ComputeProfileName)
 	}
+	a.compareSet("local_compute_profile and remote_compute_profile",
+		[]string{d.Get("local_compute_profile").(string), d.Get("remote_compute_profile").(string)}, profiles)
 
-	// Wait for task completion
-	for {
-		jr, err := GetTaskResult(client, res.Data.InterconnectTaskID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		if jr.Status == constants.SuccessStatus {
-			break
-		}
-
-		if jr.Status == constants.FailedStatus {
-			return diag.FromErr(errors.New("task failed"))
-		}
+	a.compare("uplink_max_bandwidth", d.Get("uplink_max_bandwidth").(int), sm.WanoptConfig.UplinkMaxBandwidth)
+	a.compare("app_path_resiliency_enabled", d.Get("app_path_resiliency_enabled").(bool), sm.TrafficEnggCfg.IsAppPathResiliencyEnabled)
+	a.compare("tcp_flow_conditioning_enabled", d.Get("tcp_flow_conditioning_enabled").(bool), sm.TrafficEnggCfg.IsTCPFlowConditioningEnabled)
 
-		time.Sleep(5 * time.Second)
+	configured := []string{}
+	for _, j := range d.Get("service").([]interface{}) {
+		configured = append(configured, j.(map[string]interface{})["name"].(string))
 	}
+	existing := []string{}
+	for _, s := range sm.Services {
+		existing = append(existing, s.Name)
+	}
+	a.compareSet("service", configured, existing)
 
-	return diags
+	return a.err()
 }
@@ -21,23 +21,27 @@ func resourceSitePairing() *schema.Resource {
 		UpdateContext: resourceSitePairingUpdate,
 		DeleteContext: resourceSitePairingDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"url": {
 				Type:        schema.TypeString,
 				Description: "The URL of the remote cloud.",
 				Required:    true,
+				ForceNew:    true,
 			},
 			"username": {
 				Type:        schema.TypeString,
 				Description: "The username used for remote cloud authentication.",
 				Required:    true,
 			},
-			"password": {
-				Type:        schema.TypeString,
-				Description: "The password used for remote cloud authentication.",
-				Required:    true,
-				Sensitive:   true,
-			},
+			"password":            passwordSchema("The password used for remote cloud authentication."),
+			"password_wo":         passwordWriteOnlySchema("The password used for remote cloud authentication."),
+			"password_wo_version": passwordWriteOnlyVersionSchema(),
 			"local_vc": {
 				Type:        schema.TypeString,
 				Description: "The ID of the local vCenter instance.",
@@ -89,7 +93,7 @@ func resourceSitePairingCreate(ctx context.Context, d *schema.ResourceData, m in
 
 	url := d.Get("url").(string)
 	username := d.Get("username").(string)
-	password := d.Get("password").(string)
+	password := getPassword(d)
 
 	body := RemoteCloudConfigBody{
 		Remote: RemoteData{
@@ -99,96 +103,43 @@ func resourceSitePairingCreate(ctx context.Context, d *schema.ResourceData, m in
 		},
 	}
 
-	res, err := InsertSitePairing(client, body)
-
-	if err != nil {
-		return diag.FromErr(err)
-	}
+	res, err := InsertSitePairing(ctx, client, body)
 
-	secondTry := false
-	if res.Errors != nil {
-		if res.Errors[0].Error == "Login failure" {
-			return diag.Errorf("%s", res.Errors[0].Text)
+	var hcxErr *HCXError
+	if errors.As(err, &hcxErr) && hcxErr.IsCertificateUntrusted() && hcxErr.Certificate() != "" {
+		// Trust the remote certificate and try again.
+		_, certErr := InsertCertificate(ctx, client, InsertCertificateBody{
+			Certificate: hcxErr.Certificate(),
+		})
+		if certErr != nil {
+			return diag.FromErr(certErr)
 		}
 
-		if len(res.Errors[0].Data) > 0 {
-			// Try to get certificate
-			certificateRaw := res.Errors[0].Data[0]
-			certificate, ok := certificateRaw["certificate"].(string)
-
-			if ok {
-				// Add certificate
-				body := InsertCertificateBody{
-					Certificate: certificate,
-				}
-				_, err := InsertCertificate(client, body)
-				if err != nil {
-					return diag.FromErr(err)
-				}
-			}
-		} else {
-			return diag.Errorf("Unknown error(s): %+v", res.Errors)
-		}
-
-		secondTry = true
+		res, err = InsertSitePairing(ctx, client, body)
 	}
 
-	if secondTry {
-		res, err = InsertSitePairing(client, body)
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Wait for job completion
-	count := 0
-	for {
-		jr, err := GetJobResult(client, res.Data.JobID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		if jr.IsDone {
-			break
-		}
-
-		if jr.DidFail {
-			return diag.Errorf("site pairing job failed")
-		}
-		time.Sleep(10 * time.Second)
-		count = count + 1
-		if count > 5 {
-			break
-		}
-	}
-
-	if count > 5 {
-		res, err = InsertSitePairing(client, body)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		// Wait for job completion
-		count = 0
-		for {
-			jr, err := GetJobResult(client, res.Data.JobID)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			if jr.IsDone {
-				break
-			}
-
-			if jr.DidFail {
-				return diag.Errorf("site pairing job failed")
-			}
-			time.Sleep(10 * time.Second)
-			count = count + 1
-			if count > 5 {
-				break
+	_, err = WaitForJob(ctx, client, res.Data.JobID, WaitOptions{PollInterval: 10 * time.Second, Timeout: d.Timeout(schema.TimeoutCreate)})
+	if err != nil {
+		// When the job keeps running after an interruption, look up the site pairing so that it is tracked as a
+		// tainted resource.
+		if interrupted(err) {
+			lookupCtx, cancel := detachedContext(ctx)
+			defer cancel()
+
+			if sp, lookupErr := GetSitePairings(lookupCtx, client); lookupErr == nil {
+				for _, item := range sp.Data.Items {
+					if item.URL == url {
+						d.SetId(item.EndpointID)
+					}
+				}
 			}
 		}
+		return diag.FromErr(err)
 	}
 
 	d.SetId(res.Data.JobID)
@@ -204,13 +155,13 @@ func resourceSitePairingRead(ctx context.Context, d *schema.ResourceData, m inte
 
 	url := d.Get("url").(string)
 
-	res, err := GetSitePairings(client)
+	res, err := GetSitePairings(ctx, client)
 
 	for _, item := range res.Data.Items {
 		if item.URL == url {
 			d.SetId(item.EndpointID)
 
-			lc, err := GetLocalContainer(client)
+			lc, err := GetLocalContainer(ctx, client)
 			if err != nil {
 				return diag.FromErr(errors.New("cannot get local container info"))
 			}
@@ -219,7 +170,7 @@ func resourceSitePairingRead(ctx context.Context, d *schema.ResourceData, m inte
 				return diag.FromErr(err)
 			}
 
-			rc, err := GetRemoteContainer(client)
+			rc, err := GetRemoteContainer(ctx, client)
 			if err != nil {
 				return diag.FromErr(errors.New("cannot get remote container info"))
 			}
@@ -234,7 +185,7 @@ func resourceSitePairingRead(ctx context.Context, d *schema.ResourceData, m inte
 			}
 
 			// Update Remote Cloud Info
-			res2, err := GetRemoteCloudList(client)
+			res2, err := GetRemoteCloudList(ctx, client)
 			if err != nil {
 				return diag.FromErr(errors.New("cannot get remote cloud info"))
 			}
@@ -250,7 +201,7 @@ func resourceSitePairingRead(ctx context.Context, d *schema.ResourceData, m inte
 			}
 
 			// Update Local Cloud Info
-			res3, err := GetLocalCloudList(client)
+			res3, err := GetLocalCloudList(ctx, client)
 			if err != nil {
 				return diag.FromErr(errors.New("cannot get remote cloud info"))
 			}
@@ -274,6 +225,34 @@ func resourceSitePairingRead(ctx context.Context, d *schema.ResourceData, m inte
 // resourceSitePairingUpdate updates the site pairing configuration.
 func resourceSitePairingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
+	client := m.(*Client)
+
+	if d.HasChange("username") || passwordChanged(d) {
+		body := RemoteCloudConfigBody{
+			Remote: RemoteData{
+				Username: d.Get("username").(string),
+				Password: getPassword(d),
+				URL:      d.Get("url").(string),
+			},
+		}
+
+		// Keep the previous credentials in the state until HCX accepts the new ones.
+		res, err := UpdateSitePairing(ctx, client, d.Id(), body)
+		if err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+
+		// Wait for job completion
+		if res.Data.JobID != "" {
+			_, err = WaitForJob(ctx, client, res.Data.JobID, WaitOptions{PollInterval: 10 * time.Second, Timeout: d.Timeout(schema.TimeoutUpdate)})
+			if err != nil {
+				d.Partial(true)
+				return diag.FromErr(err)
+			}
+		}
+	}
+
 	return resourceSitePairingRead(ctx, d, m)
 }
 
@@ -284,31 +263,29 @@ func resourceSitePairingDelete(ctx context.Context, d *schema.ResourceData, m in
 	client := m.(*Client)
 	url := d.Get("url").(string)
 
-	_, err := DeleteSitePairings(client, d.Id())
+	_, err := DeleteSitePairings(ctx, client, d.Id())
 
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Wait for site pairing deletion
-	for {
-		res, err := GetSitePairings(client)
+	err = waitUntil(ctx, WaitOptions{Timeout: d.Timeout(schema.TimeoutDelete)}, "site pairing deletion", d.Id(), func(ctx context.Context) (bool, error) {
+		res, err := GetSitePairings(ctx, client)
 		if err != nil {
-			return diag.FromErr(err)
+			return false, err
 		}
 
-		found := false
 		for _, item := range res.Data.Items {
 			if item.URL == url {
-				found = true
+				return false, nil
 			}
 		}
 
-		if !found {
-			break
-		}
-
-		time.Sleep(5 * time.Second)
+		return true, nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	return diags
@@ -0,0 +1,64 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSitePairingDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "endpoint-1",
+		Attributes: map[string]string{
+			"id":       "endpoint-1",
+			"url":      "https://hcx1.example.com",
+			"username": "admin",
+			"password": "secret",
+		},
+	}
+
+	tests := []struct {
+		name            string
+		config          map[string]interface{}
+		wantRequiresNew bool
+	}{
+		{
+			name: "url",
+			config: map[string]interface{}{
+				"url":      "https://hcx2.example.com",
+				"username": "admin",
+				"password": "secret",
+			},
+			wantRequiresNew: true,
+		},
+		{
+			name: "credentials",
+			config: map[string]interface{}{
+				"url":      "https://hcx1.example.com",
+				"username": "operator",
+				"password": "rotated",
+			},
+			wantRequiresNew: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := resourceSitePairing().Diff(context.Background(), state, terraform.NewResourceConfigRaw(tt.config), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if diff == nil || diff.Empty() {
+				t.Fatal("Diff() is empty")
+			}
+			if got := diff.RequiresNew(); got != tt.wantRequiresNew {
+				t.Errorf("RequiresNew() = %v, want %v", got, tt.wantRequiresNew)
+			}
+		})
+	}
+}
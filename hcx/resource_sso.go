@@ -6,6 +6,8 @@ package hcx
 
 import (
 	"context"
+	"fmt"
+	"log"
 
 	"github.com/vmware/terraform-provider-hcx/hcx/constants"
 
@@ -20,6 +22,9 @@ func resourceSSO() *schema.Resource {
 		ReadContext:   resourceSSORead,
 		UpdateContext: resourceSSOUpdate,
 		DeleteContext: resourceSSODelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSSOImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"url": {
@@ -57,14 +62,14 @@ func resourceSSOCreate(ctx context.Context, d *schema.ResourceData, m interface{
 	}
 
 	// First, check if SSO config is already present
-	res, err := GetSSO(client)
+	res, err := GetSSO(ctx, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	if len(res.InsertSSOData.Items) == 0 {
 		// No SSO configuration found.
-		res, err := InsertSSO(client, body)
+		res, err := InsertSSO(ctx, client, body)
 
 		if err != nil {
 			return diag.FromErr(err)
@@ -84,6 +89,28 @@ func resourceSSOCreate(ctx context.Context, d *schema.ResourceData, m interface{
 func resourceSSORead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
+	client := m.(*Client)
+
+	res, err := GetSSO(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, j := range res.InsertSSOData.Items {
+		if j.Config.UUID != d.Id() {
+			continue
+		}
+
+		if err := d.Set("url", j.Config.LookupServiceURL); err != nil {
+			return diag.FromErr(err)
+		}
+
+		return diags
+	}
+
+	log.Printf("[WARN] SSO configuration %s not found, removing from state", d.Id())
+	d.SetId("")
+
 	return diags
 }
 
@@ -108,12 +135,24 @@ func resourceSSOUpdate(ctx context.Context, d *schema.ResourceData, m interface{
 		},
 	}
 
-	_, err := UpdateSSO(client, body)
+	_, err := UpdateSSO(ctx, client, body)
 
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// Verify that HCX applied the lookup service URL.
+	res, err := GetSSO(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, j := range res.InsertSSOData.Items {
+		if j.Config.UUID == d.Id() && j.Config.LookupServiceURL != url {
+			return diag.Errorf("SSO configuration %s has lookup service URL %q instead of %q after the update", d.Id(),
+				j.Config.LookupServiceURL, url)
+		}
+	}
+
 	return resourceSSORead(ctx, d, m)
 }
 
@@ -123,10 +162,29 @@ func resourceSSODelete(ctx context.Context, d *schema.ResourceData, m interface{
 
 	client := m.(*Client)
 
-	_, err := DeleteSSO(client, d.Id())
+	_, err := DeleteSSO(ctx, client, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	return diags
 }
+
+// resourceSSOImport imports an SSO configuration by its UUID or its lookup service URL.
+func resourceSSOImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
+
+	res, err := GetSSO(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, j := range res.InsertSSOData.Items {
+		if j.Config.UUID == d.Id() || j.Config.LookupServiceURL == d.Id() {
+			d.SetId(j.Config.UUID)
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("SSO configuration %s: %w", d.Id(), ErrNotFound)
+}
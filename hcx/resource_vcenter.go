@@ -6,6 +6,8 @@ package hcx
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"time"
 
 	b64 "encoding/base64"
@@ -23,6 +25,15 @@ func resourcevCenter() *schema.Resource {
 		ReadContext:   resourcevCenterRead,
 		UpdateContext: resourcevCenterUpdate,
 		DeleteContext: resourcevCenterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcevCenterImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"url": {
@@ -35,10 +46,13 @@ func resourcevCenter() *schema.Resource {
 				Description: "The username to authenticate with the vCenter instance.",
 				Required:    true,
 			},
-			"password": {
+			"password":            passwordSchema("The password to authenticate with the vCenter instance."),
+			"password_wo":         passwordWriteOnlySchema("The password to authenticate with the vCenter instance."),
+			"password_wo_version": passwordWriteOnlyVersionSchema(),
+			"vcuuid": {
 				Type:        schema.TypeString,
-				Description: "The password to authenticate with the vCenter instance.",
-				Required:    true,
+				Description: "The instance UUID of the vCenter instance.",
+				Computed:    true,
 			},
 		},
 	}
@@ -51,7 +65,7 @@ func resourcevCenterCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 	url := d.Get("url").(string)
 	username := d.Get("username").(string)
-	password := d.Get("password").(string)
+	password := getPassword(d)
 
 	body := InsertvCenterBody{
 		Data: InsertvCenterData{
@@ -67,7 +81,7 @@ func resourcevCenterCreate(ctx context.Context, d *schema.ResourceData, m interf
 		},
 	}
 
-	res, err := InsertvCenter(client, body)
+	res, err := InsertvCenter(ctx, client, body)
 
 	if err != nil {
 		return diag.FromErr(err)
@@ -75,51 +89,45 @@ func resourcevCenterCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 	d.SetId(res.InsertvCenterData.Items[0].Config.UUID)
 
-	// Restart App Daemon
-	_, err = AppEngineStop(client)
-	if err != nil {
+	if err := restartAppEngine(ctx, client, d.Timeout(schema.TimeoutCreate)); err != nil {
 		return diag.FromErr(err)
 	}
 
-	// Wait for App Daemon to be stopped
-	for {
-		jr, err := GetAppEngineStatus(client)
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	return resourcevCenterRead(ctx, d, m)
+}
 
-		if jr.Result == constants.StoppedStatus {
-			break
-		}
-		time.Sleep(5 * time.Second)
-	}
+// resourcevCenterRead retrieves the vCenter instance configuration.
+func resourcevCenterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(*Client)
 
-	_, err = AppEngineStart(client)
+	res, err := GetvCenter(ctx, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	// Wait for App Daemon to be started
-	for {
-		jr, err := GetAppEngineStatus(client)
-		if err != nil {
-			return diag.FromErr(err)
+	for _, j := range res.InsertvCenterData.Items {
+		if j.Config.UUID != d.Id() {
+			continue
 		}
 
-		if jr.Result == constants.RunningStatus {
-			break
+		// The password is not returned by HCX.
+		if err := d.Set("url", j.Config.URL); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("username", j.Config.Username); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("vcuuid", j.Config.VcUUID); err != nil {
+			return diag.FromErr(err)
 		}
-		time.Sleep(5 * time.Second)
-	}
-	// Seems that we need to wait a bit
-	time.Sleep(60 * time.Second)
 
-	return resourcevCenterRead(ctx, d, m)
-}
+		return diags
+	}
 
-// resourcevCenterRead retrieves the vCenter instance configuration.
-func resourcevCenterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
+	log.Printf("[WARN] vCenter configuration %s not found, removing from state", d.Id())
+	d.SetId("")
 
 	return diags
 }
@@ -127,6 +135,39 @@ func resourcevCenterRead(ctx context.Context, d *schema.ResourceData, m interfac
 // resourcevCenterUpdate updates the vCenter instance configuration.
 func resourcevCenterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
+	client := m.(*Client)
+
+	if d.HasChanges("url", "username") || passwordChanged(d) {
+		body := InsertvCenterBody{
+			Data: InsertvCenterData{
+				Items: []InsertvCenterDataItem{
+					{
+						Config: InsertvCenterDataItemConfig{
+							Username: d.Get("username").(string),
+							Password: b64.StdEncoding.EncodeToString([]byte(getPassword(d))),
+							URL:      d.Get("url").(string),
+							UUID:     d.Id(),
+						},
+					},
+				},
+			},
+		}
+
+		// Keep the previous credentials in the state until HCX accepts the new ones.
+		_, err := UpdatevCenter(ctx, client, d.Id(), body)
+		if err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+
+		// The credentials are used as is, but the App Engine only connects to another vCenter instance on restart.
+		if d.HasChange("url") {
+			if err := restartAppEngine(ctx, client, d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
 	return resourcevCenterRead(ctx, d, m)
 }
 
@@ -136,10 +177,57 @@ func resourcevCenterDelete(ctx context.Context, d *schema.ResourceData, m interf
 
 	client := m.(*Client)
 
-	_, err := DeletevCenter(client, d.Id())
+	_, err := DeletevCenter(ctx, client, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	return diags
 }
+
+// resourcevCenterImport imports a vCenter instance configuration by its UUID or its URL.
+func resourcevCenterImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
+
+	res, err := GetvCenter(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, j := range res.InsertvCenterData.Items {
+		if j.Config.UUID == d.Id() || j.Config.URL == d.Id() {
+			d.SetId(j.Config.UUID)
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("vCenter configuration %s: %w", d.Id(), ErrNotFound)
+}
+
+// restartAppEngine stops and starts the App Engine, so that it applies the vCenter instance configuration.
+func restartAppEngine(ctx context.Context, client *Client, timeout time.Duration) error {
+	_, err := AppEngineStop(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	// Wait for App Daemon to be stopped
+	err = WaitForAppEngine(ctx, client, constants.StoppedStatus, WaitOptions{Timeout: timeout})
+	if err != nil {
+		return err
+	}
+
+	_, err = AppEngineStart(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	// Wait for App Daemon to be started
+	err = WaitForAppEngine(ctx, client, constants.RunningStatus, WaitOptions{Timeout: timeout})
+	if err != nil {
+		return err
+	}
+
+	// Seems that we need to wait a bit
+	return sleepContext(ctx, 60*time.Second)
+}
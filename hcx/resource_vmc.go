@@ -24,6 +24,11 @@ func resourceVmc() *schema.Resource {
 		UpdateContext: resourceVmcUpdate,
 		DeleteContext: resourceVmcDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(120 * time.Minute),
+			Delete: schema.DefaultTimeout(120 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"sddc_id": {
 				Type:         schema.TypeString,
@@ -61,26 +66,26 @@ func resourceVmcCreate(ctx context.Context, d *schema.ResourceData, m interface{
 
 	client := m.(*Client)
 
-	token := client.Token
+	token := client.VmcToken
 	sddcName := d.Get("sddc_name").(string)
 	sddcID := d.Get("sddc_id").(string)
 
 	// Authenticate with VMware Cloud Services
-	accessToken, err := VmcAuthenticate(token)
+	accessToken, err := VmcAuthenticate(ctx, client, token)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	err = CloudAuthenticate(client, accessToken)
+	err = CloudAuthenticate(ctx, client, accessToken)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	var sddc SDDC
 	if sddcID != "" {
-		sddc, err = GetSddcByID(client, sddcID)
+		sddc, err = GetSddcByID(ctx, client, sddcID)
 	} else {
-		sddc, err = GetSddcByName(client, sddcName)
+		sddc, err = GetSddcByName(ctx, client, sddcName)
 	}
 
 	if err != nil {
@@ -93,39 +98,15 @@ func resourceVmcCreate(ctx context.Context, d *schema.ResourceData, m interface{
 	}
 
 	// Activate HCX.
-	_, err = ActivateHcxOnSDDC(client, sddc.ID)
+	_, err = ActivateHcxOnSDDC(ctx, client, sddc.ID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Wait for task to be completed.
-	errcount := 0
-	for {
-		if sddcID != "" {
-			sddc, err = GetSddcByID(client, sddcID)
-		} else {
-			sddc, err = GetSddcByName(client, sddcName)
-		}
-		if err != nil {
-			// Attempt to bypass recurring situation where the HCX API
-			// returns status 502 with a proxy server error, and an HTML response
-			// instead of JSON.
-			errcount++
-			hclog.Default().Info("[INFO] - resourceVmcCreate() - Error retrieving SDDC status: ", "error", err.Error(), "Errcount:", errcount)
-			if errcount > 12 {
-				return diag.FromErr(err)
-			}
-		}
-
-		if sddc.DeploymentStatus == constants.VmcActivationActiveStatus {
-			break
-		}
-
-		if sddc.DeploymentStatus == constants.VmcActivationFailedStatus {
-			return diag.Errorf("Activation failed")
-		}
-
-		time.Sleep(constants.VmcRetryInterval)
+	err = waitForSddcStatus(ctx, client, sddcID, sddcName, constants.VmcActivationActiveStatus, constants.VmcActivationFailedStatus, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	return resourceVmcRead(ctx, d, m)
@@ -137,38 +118,32 @@ func resourceVmcRead(ctx context.Context, d *schema.ResourceData, m interface{})
 
 	client := m.(*Client)
 
-	token := client.Token
+	token := client.VmcToken
 	sddcName := d.Get("sddc_name").(string)
 	sddcID := d.Get("sddc_id").(string)
 
-	log.Printf("******************************************************************\n")
-	log.Printf("token: %s, sddc_name: %s,   sddc: %s   \n", token, sddcName, sddcID)
-	log.Printf("******************************************************************\n")
+	log.Printf("[DEBUG] Reading SDDC, sddc_name: %s, sddc_id: %s", sddcName, sddcID)
 
 	if sddcName == "" && sddcID == "" {
 		return diag.Errorf("SDDC name or Id must be specified")
 	}
 
 	// Authenticate with VMware Cloud Services
-	accessToken, err := VmcAuthenticate(token)
+	accessToken, err := VmcAuthenticate(ctx, client, token)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	err = CloudAuthenticate(client, accessToken)
+	err = CloudAuthenticate(ctx, client, accessToken)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	log.Printf("****************")
-	log.Printf("[Client inside]: %+v ", client)
-	log.Printf("****************")
-
 	var sddc SDDC
 	if sddcID != "" {
-		sddc, err = GetSddcByID(client, sddcID)
+		sddc, err = GetSddcByID(ctx, client, sddcID)
 	} else {
-		sddc, err = GetSddcByName(client, sddcName)
+		sddc, err = GetSddcByName(ctx, client, sddcName)
 	}
 	if err != nil {
 		return diag.FromErr(err)
@@ -200,67 +175,83 @@ func resourceVmcDelete(ctx context.Context, d *schema.ResourceData, m interface{
 
 	client := m.(*Client)
 
-	token := client.Token
+	token := client.VmcToken
 	sddcName := d.Get("sddc_name").(string)
 	sddcID := d.Get("sddc_id").(string)
 
 	// Authenticate with VMware Cloud Services
-	accessToken, err := VmcAuthenticate(token)
+	accessToken, err := VmcAuthenticate(ctx, client, token)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	err = CloudAuthenticate(client, accessToken)
+	err = CloudAuthenticate(ctx, client, accessToken)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	var sddc SDDC
 	if sddcID != "" {
-		sddc, err = GetSddcByID(client, sddcID)
+		sddc, err = GetSddcByID(ctx, client, sddcID)
 	} else {
-		sddc, err = GetSddcByName(client, sddcName)
+		sddc, err = GetSddcByName(ctx, client, sddcName)
 	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Deactivate HCX
-	_, err = DeactivateHcxOnSDDC(client, sddc.ID)
+	_, err = DeactivateHcxOnSDDC(ctx, client, sddc.ID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Wait for task to be completed
+	err = waitForSddcStatus(ctx, client, sddcID, sddcName, constants.VmcDeactivationInactiveStatus, constants.VmcDeactivationFailedStatus, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// waitForSddcStatus polls the SDDC identified by sddcID, or by sddcName when the ID is empty, until its HCX deployment
+// status matches target or the timeout expires. Returns an error if the status matches failed.
+func waitForSddcStatus(ctx context.Context, client *Client, sddcID, sddcName, target, failed string, timeout time.Duration) error {
+	opts := WaitOptions{
+		Timeout:         timeout,
+		PollInterval:    constants.VmcRetryInterval,
+		MaxPollInterval: constants.VmcRetryInterval,
+	}
+
 	errcount := 0
-	for {
+	return waitUntil(ctx, opts, "SDDC", sddcID+sddcName, func(ctx context.Context) (bool, error) {
 		var sddc SDDC
+		var err error
 		if sddcID != "" {
-			sddc, err = GetSddcByID(client, sddcID)
+			sddc, err = GetSddcByID(ctx, client, sddcID)
 		} else {
-			sddc, err = GetSddcByName(client, sddcName)
+			sddc, err = GetSddcByName(ctx, client, sddcName)
 		}
 		if err != nil {
 			// Attempt to bypass recurring situation where the HCX API
 			// returns status 502 with a proxy server error, and an HTML response
 			// instead of JSON.
 			errcount++
-			hclog.Default().Info("[INFO] - resourceVmcDelete() - Error retrieving SDDC status: ", "error", err.Error(), "Errcount:", errcount)
+			hclog.Default().Info("[INFO] - waitForSddcStatus() - Error retrieving SDDC status: ", "error", err.Error(), "Errcount:", errcount)
 			if errcount > constants.VmcMaxRetries {
-				return diag.FromErr(err)
+				return false, err
 			}
+			return false, nil
 		}
 
-		if sddc.DeploymentStatus == constants.VmcDeactivationInactiveStatus {
-			break
-		}
-
-		if sddc.DeploymentStatus == constants.VmcDeactivationFailedStatus {
-			return diag.Errorf("Deactivation failed")
+		switch sddc.DeploymentStatus {
+		case target:
+			return true, nil
+		case failed:
+			return false, &WaitError{Kind: "SDDC", ID: sddc.ID, State: failed}
 		}
 
-		time.Sleep(constants.VmcRetryInterval)
-	}
-
-	return diags
+		return false, nil
+	})
 }
@@ -0,0 +1,120 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Default retry settings, used when the provider does not override them.
+const (
+	DefaultMaxRetries   = 5
+	DefaultRetryMinWait = 2 * time.Second
+	DefaultRetryMaxWait = 60 * time.Second
+)
+
+// RetryPolicy defines how the client retries requests that fail with a transient error: a temporary network error, a
+// 429 (Too Many Requests) or a 5xx response. Only idempotent requests are retried unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	MaxRetries         int
+	MinWait            time.Duration
+	MaxWait            time.Duration
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by clients unless configured otherwise.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinWait:    DefaultRetryMinWait,
+		MaxWait:    DefaultRetryMaxWait,
+	}
+}
+
+// idempotentKey is the context key used to mark a request as safe to retry regardless of its method.
+type idempotentKey struct{}
+
+// withIdempotent marks a request as safe to retry. It is used for POST requests that only query data, such as the
+// HCX inventory and filter APIs, and for PUT requests that replace a configuration without starting an HCX job or
+// task.
+func withIdempotent(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), idempotentKey{}, true))
+}
+
+// isIdempotent reports whether a request can be sent more than once without side effects. PUT and DELETE requests
+// are not retried by default, since most of them start an HCX job or task that a replay would start again.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	marked, _ := req.Context().Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// retryable reports whether the outcome of an attempt is a transient failure worth retrying.
+func (p RetryPolicy) retryable(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return temporaryNetworkError(err)
+	}
+
+	return res.StatusCode == http.StatusTooManyRequests ||
+		(res.StatusCode >= 500 && res.StatusCode != http.StatusNotImplemented)
+}
+
+// backoff returns the wait before the next attempt. The wait grows exponentially from MinWait up to MaxWait with
+// jitter, and honors the Retry-After header of 429 and 503 responses when present.
+func (p RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if s, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && s > 0 {
+			return min(time.Duration(s)*time.Second, p.MaxWait)
+		}
+	}
+
+	wait := p.MinWait
+	for i := 0; i < attempt && wait < p.MaxWait; i++ {
+		wait *= 2
+	}
+	wait = min(wait, p.MaxWait)
+
+	// Equal jitter: keep half of the wait and randomize the other half.
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+
+	return half + rand.N(half) // #nosec G404
+}
+
+// temporaryNetworkError reports whether err is a network error that may not happen again: a timeout, a refused or
+// reset connection, a connection closed before the response, or a temporary DNS failure. Other errors, such as
+// certificate verification failures, are permanent.
+func temporaryNetworkError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -26,7 +27,7 @@ type RoleMappingResult struct {
 
 // PutRoleMapping sends a PUT request to update role mappings using the provided body and returns the resulting
 // RoleMappingResult object. Returns an error if the request fails or the response cannot be parsed.
-func PutRoleMapping(c *Client, body []RoleMapping) (RoleMappingResult, error) {
+func PutRoleMapping(ctx context.Context, c *Client, body []RoleMapping) (RoleMappingResult, error) {
 
 	resp := RoleMappingResult{}
 
@@ -36,12 +37,12 @@ func PutRoleMapping(c *Client, body []RoleMapping) (RoleMappingResult, error) {
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s:9443/api/admin/global/config/roleMappings", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s:9443/api/admin/global/config/roleMappings", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create PUT request: %w", err)
 	}
 
-	_, r, err := c.doAdminRequest(req)
+	_, r, err := c.doAdminRequest(withIdempotent(req))
 	if err != nil {
 		return resp, fmt.Errorf("failed to send PUT request: %w", err)
 	}
@@ -53,3 +54,27 @@ func PutRoleMapping(c *Client, body []RoleMapping) (RoleMappingResult, error) {
 
 	return resp, nil
 }
+
+// GetRoleMapping sends a GET request to retrieve the role mappings. Returns an error if the request fails or the
+// response cannot be parsed.
+func GetRoleMapping(ctx context.Context, c *Client) ([]RoleMapping, error) {
+
+	resp := []RoleMapping{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s:9443/api/admin/global/config/roleMappings", c.HostURL), nil)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create GET request: %w", err)
+	}
+
+	_, r, err := c.doAdminRequest(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send GET request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	return resp, nil
+}
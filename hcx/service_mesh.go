@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -38,6 +39,7 @@ type SwitchPairCount struct {
 
 // InsertServiceMeshBody represents the body structure required to insert a service mesh configuration.
 type InsertServiceMeshBody struct {
+	ServiceMeshID   string            `json:"serviceMeshId,omitempty"`
 	Name            string            `json:"name"`
 	ComputeProfiles []ComputeProfile  `json:"computeProfiles"`
 	WanoptConfig    WanoptConfig      `json:"wanoptConfig"`
@@ -69,9 +71,25 @@ type DeleteServiceMeshData struct {
 	ServiceMeshID      string `json:"serviceMeshId"`
 }
 
+// ServiceMesh represents a service mesh configuration as returned by HCX.
+type ServiceMesh struct {
+	ServiceMeshID   string            `json:"serviceMeshId"`
+	Name            string            `json:"name"`
+	ComputeProfiles []ComputeProfile  `json:"computeProfiles"`
+	WanoptConfig    WanoptConfig      `json:"wanoptConfig"`
+	TrafficEnggCfg  TrafficEnggCfg    `json:"trafficEnggCfg"`
+	Services        []Service         `json:"services"`
+	SwitchPairCount []SwitchPairCount `json:"switchPairCount"`
+}
+
+// GetServiceMeshesResult represents the list of service meshes returned by HCX.
+type GetServiceMeshesResult struct {
+	Items []ServiceMesh `json:"items"`
+}
+
 // InsertServiceMesh sends a request to create a new service mesh using the provided body and returns the resulting
 // InsertServiceMeshResult object. Returns an error if the request fails or the response cannot be parsed.
-func InsertServiceMesh(c *Client, body InsertServiceMeshBody) (InsertServiceMeshResult, error) {
+func InsertServiceMesh(ctx context.Context, c *Client, body InsertServiceMeshBody) (InsertServiceMeshResult, error) {
 
 	resp := InsertServiceMeshResult{}
 
@@ -81,7 +99,7 @@ func InsertServiceMesh(c *Client, body InsertServiceMeshBody) (InsertServiceMesh
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/interconnect/serviceMesh", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/interconnect/serviceMesh", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -99,14 +117,63 @@ func InsertServiceMesh(c *Client, body InsertServiceMeshBody) (InsertServiceMesh
 	return resp, nil
 }
 
+// UpdateServiceMesh sends a request to edit the service mesh identified by serviceMeshID using the provided body and
+// returns the resulting InsertServiceMeshResult object. Returns an error if the request fails or the response cannot be
+// parsed.
+func UpdateServiceMesh(ctx context.Context, c *Client, serviceMeshID string, body InsertServiceMeshBody) (InsertServiceMeshResult, error) {
+
+	resp := InsertServiceMeshResult{}
+
+	body.ServiceMeshID = serviceMeshID
+
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/hybridity/api/interconnect/serviceMesh/%s", c.HostURL, serviceMeshID), &buf)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create PUT request: %w", err)
+	}
+
+	_, r, err := c.doRequest(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send PUT request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ResyncServiceMesh sends a request to edit the service mesh with its current configuration, so that HCX redeploys its
+// appliances according to the current compute profiles. Returns the resulting InsertServiceMeshResult object or an
+// error if the request fails or the response cannot be parsed.
+func ResyncServiceMesh(ctx context.Context, c *Client, sm ServiceMesh) (InsertServiceMeshResult, error) {
+	body := InsertServiceMeshBody{
+		Name:            sm.Name,
+		ComputeProfiles: sm.ComputeProfiles,
+		WanoptConfig:    sm.WanoptConfig,
+		TrafficEnggCfg:  sm.TrafficEnggCfg,
+		Services:        sm.Services,
+		SwitchPairCount: sm.SwitchPairCount,
+	}
+
+	return UpdateServiceMesh(ctx, c, sm.ServiceMeshID, body)
+}
+
 // DeleteServiceMesh sends a request to remove a service mesh identified by the serviceMeshID. The force parameter
 // determines whether to forcibly delete it. Returns the resulting DeleteServiceMeshResult object or an error if the
 // request fails or the response cannot be parsed.
-func DeleteServiceMesh(c *Client, serviceMeshID string, force bool) (DeleteServiceMeshResult, error) {
+func DeleteServiceMesh(ctx context.Context, c *Client, serviceMeshID string, force bool) (DeleteServiceMeshResult, error) {
 
 	resp := DeleteServiceMeshResult{}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/hybridity/api/interconnect/serviceMesh/%s?force=%v", c.HostURL, serviceMeshID, force), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/hybridity/api/interconnect/serviceMesh/%s?force=%v", c.HostURL, serviceMeshID, force), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create DELETE request: %w", err)
 	}
@@ -123,3 +190,72 @@ func DeleteServiceMesh(c *Client, serviceMeshID string, force bool) (DeleteServi
 
 	return resp, nil
 }
+
+// GetServiceMeshes sends a request to retrieve the list of service meshes. Returns an error if the request fails or the
+// response cannot be parsed.
+func GetServiceMeshes(ctx context.Context, c *Client) ([]ServiceMesh, error) {
+
+	resp := GetServiceMeshesResult{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/hybridity/api/interconnect/serviceMesh", c.HostURL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GET request: %w", err)
+	}
+
+	_, r, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send GET request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	return resp.Items, nil
+}
+
+// GetServiceMesh sends a request to retrieve the service mesh identified by serviceMeshID. Returns an error wrapping
+// ErrNotFound if the service mesh does not exist.
+func GetServiceMesh(ctx context.Context, c *Client, serviceMeshID string) (ServiceMesh, error) {
+
+	resp := ServiceMesh{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/hybridity/api/interconnect/serviceMesh/%s", c.HostURL, serviceMeshID), nil)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create GET request: %w", err)
+	}
+
+	_, r, err := c.doRequest(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send GET request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	if resp.ServiceMeshID == "" {
+		return resp, fmt.Errorf("service mesh %s: %w", serviceMeshID, ErrNotFound)
+	}
+
+	return resp, nil
+}
+
+// GetServiceMeshByName returns the service mesh with the provided name. Returns an error wrapping ErrNotFound if no
+// service mesh has this name.
+func GetServiceMeshByName(ctx context.Context, c *Client, name string) (ServiceMesh, error) {
+	meshes, err := GetServiceMeshes(ctx, c)
+	if err != nil {
+		return ServiceMesh{}, err
+	}
+
+	for _, sm := range meshes {
+		if sm.Name == name {
+			return sm, nil
+		}
+	}
+
+	return ServiceMesh{}, fmt.Errorf("service mesh %s: %w", name, ErrNotFound)
+}
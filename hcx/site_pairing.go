@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -72,8 +73,9 @@ type DeleteRemoteCloudConfigResult struct {
 }
 
 // InsertSitePairing sends a request to create a new site pairing using the provided body and returns the resulting
-// PostRemoteCloudConfigResult object. Returns an error if the request fails or the response cannot be parsed.
-func InsertSitePairing(c *Client, body RemoteCloudConfigBody) (PostRemoteCloudConfigResult, error) {
+// PostRemoteCloudConfigResult object. Returns an error if the request fails or the response cannot be parsed, and an
+// *HCXError if the response reports errors, such as a login failure or an untrusted remote certificate.
+func InsertSitePairing(ctx context.Context, c *Client, body RemoteCloudConfigBody) (PostRemoteCloudConfigResult, error) {
 	resp := PostRemoteCloudConfigResult{}
 
 	var buf bytes.Buffer
@@ -82,12 +84,12 @@ func InsertSitePairing(c *Client, body RemoteCloudConfigBody) (PostRemoteCloudCo
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/cloudConfigs", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/cloudConfigs", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	res, r, err := c.doRequest(req)
 	if err != nil {
 		return resp, fmt.Errorf("failed to send POST request: %w", err)
 	}
@@ -97,15 +99,74 @@ func InsertSitePairing(c *Client, body RemoteCloudConfigBody) (PostRemoteCloudCo
 		return resp, fmt.Errorf("failed to parse HTTP response: %w", err)
 	}
 
+	if len(resp.Errors) > 0 {
+		return resp, cloudConfigError(req, res, r, resp.Errors)
+	}
+
 	return resp, nil
 }
 
+// UpdateSitePairing sends a request to update the credentials of the site pairing identified by the provided
+// endpointID using the provided body and returns the resulting PostRemoteCloudConfigResult object. Returns an error if
+// the request fails or the response cannot be parsed, and an *HCXError if the response reports errors, such as a login
+// failure.
+func UpdateSitePairing(ctx context.Context, c *Client, endpointID string, body RemoteCloudConfigBody) (PostRemoteCloudConfigResult, error) {
+	resp := PostRemoteCloudConfigResult{}
+
+	body.Remote.EndpointID = endpointID
+
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/hybridity/api/cloudConfigs/%s", c.HostURL, endpointID), &buf)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create PUT request: %w", err)
+	}
+
+	res, r, err := c.doRequest(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send PUT request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	if len(resp.Errors) > 0 {
+		return resp, cloudConfigError(req, res, r, resp.Errors)
+	}
+
+	return resp, nil
+}
+
+// cloudConfigError returns an *HCXError for the errors reported in the response to a remote cloud configuration
+// request.
+func cloudConfigError(req *http.Request, res *http.Response, r []byte, errs []PostRemoteCloudConfigResultError) error {
+	hcxErr := &HCXError{
+		StatusCode: res.StatusCode,
+		Method:     req.Method,
+		Endpoint:   req.URL.Path,
+		Body:       string(r),
+	}
+	items := []hcxErrorItem{}
+	for _, e := range errs {
+		items = append(items, hcxErrorItem{Error: e.Error, Text: e.Text, Data: e.Data})
+	}
+	hcxErr.fromItems(items)
+
+	return hcxErr
+}
+
 // GetSitePairings sends a GET request to retrieve all existing site pairings and returns the resulting
 // GetRemoteCloudConfigResult object. Returns an error if the request fails or the response cannot be parsed.
-func GetSitePairings(c *Client) (GetRemoteCloudConfigResult, error) {
+func GetSitePairings(ctx context.Context, c *Client) (GetRemoteCloudConfigResult, error) {
 	resp := GetRemoteCloudConfigResult{}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/hybridity/api/cloudConfigs", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/hybridity/api/cloudConfigs", c.HostURL), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -126,10 +187,10 @@ func GetSitePairings(c *Client) (GetRemoteCloudConfigResult, error) {
 // DeleteSitePairings sends a DELETE request to remove a site pairing identified by the provided endpointID and returns
 // the resulting DeleteRemoteCloudConfigResult object. Returns an error if the request fails or the response cannot be
 // parsed.
-func DeleteSitePairings(c *Client, endpointID string) (DeleteRemoteCloudConfigResult, error) {
+func DeleteSitePairings(ctx context.Context, c *Client, endpointID string) (DeleteRemoteCloudConfigResult, error) {
 	resp := DeleteRemoteCloudConfigResult{}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/hybridity/api/endpointPairing/%s", c.HostURL, endpointID), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/hybridity/api/endpointPairing/%s", c.HostURL, endpointID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create DELETE request: %w", err)
 	}
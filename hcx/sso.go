@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -52,10 +53,10 @@ type GetSSOResult struct {
 
 // GetSSO sends a GET request to retrieve the current SSO configuration and returns the resulting GetSSOResult object.
 // Returns an error if the request fails or the response cannot be parsed.
-func GetSSO(c *Client) (GetSSOResult, error) {
+func GetSSO(ctx context.Context, c *Client) (GetSSOResult, error) {
 	resp := GetSSOResult{}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s:9443/api/admin/global/config/lookupservice", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s:9443/api/admin/global/config/lookupservice", c.HostURL), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -75,7 +76,7 @@ func GetSSO(c *Client) (GetSSOResult, error) {
 
 // InsertSSO sends a POST request to create a new SSO configuration using the provided body and returns the resulting
 // InsertSSOResult object. Returns an error if the request fails or the response cannot be parsed.
-func InsertSSO(c *Client, body InsertSSOBody) (InsertSSOResult, error) {
+func InsertSSO(ctx context.Context, c *Client, body InsertSSOBody) (InsertSSOResult, error) {
 	resp := InsertSSOResult{}
 
 	var buf bytes.Buffer
@@ -84,7 +85,7 @@ func InsertSSO(c *Client, body InsertSSOBody) (InsertSSOResult, error) {
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s:9443/api/admin/global/config/lookupservice", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s:9443/api/admin/global/config/lookupservice", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -104,7 +105,7 @@ func InsertSSO(c *Client, body InsertSSOBody) (InsertSSOResult, error) {
 
 // UpdateSSO sends a POST request to update the existing SSO configuration using the provided body. It returns the
 // resulting InsertSSOResult object or an error if the request fails or the response cannot be parsed.
-func UpdateSSO(c *Client, body InsertSSOBody) (InsertSSOResult, error) {
+func UpdateSSO(ctx context.Context, c *Client, body InsertSSOBody) (InsertSSOResult, error) {
 	resp := InsertSSOResult{}
 
 	var buf bytes.Buffer
@@ -113,7 +114,7 @@ func UpdateSSO(c *Client, body InsertSSOBody) (InsertSSOResult, error) {
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s:9443/api/admin/global/config/lookupservice/%s", c.HostURL, body.Data.Items[0].Config.UUID), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s:9443/api/admin/global/config/lookupservice/%s", c.HostURL, body.Data.Items[0].Config.UUID), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -133,10 +134,10 @@ func UpdateSSO(c *Client, body InsertSSOBody) (InsertSSOResult, error) {
 
 // DeleteSSO sends a DELETE request to remove the SSO configuration identified by the provided SSOUUID and returns the
 // resulting DeleteSSOResult object. Returns an error if the request fails or the response cannot be parsed.
-func DeleteSSO(c *Client, SSOUUID string) (DeleteSSOResult, error) {
+func DeleteSSO(ctx context.Context, c *Client, SSOUUID string) (DeleteSSOResult, error) {
 	resp := DeleteSSOResult{}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s:9443/api/admin/global/config/lookupservice/%s", c.HostURL, SSOUUID), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s:9443/api/admin/global/config/lookupservice/%s", c.HostURL, SSOUUID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create DELETE request: %w", err)
 	}
@@ -0,0 +1,164 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// TransportConfig represents the connection settings used to build the dedicated HTTP transport of a Client.
+type TransportConfig struct {
+	AllowUnverifiedSSL bool
+	ProxyURL           string
+	NoProxy            string
+	CAFile             string
+	CAPEM              string
+	ServerFingerprints []string
+}
+
+// newTransport builds an HTTP transport from the provided configuration. The transport starts from a clone of the
+// standard library defaults, so http.DefaultTransport itself is never modified.
+func newTransport(cfg TransportConfig) (*http.Transport, error) {
+	proxy, err := proxyFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 10
+	transport.IdleConnTimeout = 90 * time.Second
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
+
+// proxyFunc returns the proxy selection function for the provided configuration. When no proxy URL is configured, the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used, with NoProxy overriding NO_PROXY if set.
+func proxyFunc(cfg TransportConfig) (func(*http.Request) (*url.URL, error), error) {
+	proxyConfig := httpproxy.FromEnvironment()
+
+	if cfg.ProxyURL != "" {
+		if _, err := url.Parse(cfg.ProxyURL); err != nil {
+			return nil, fmt.Errorf("invalid proxy_url %q: %w", cfg.ProxyURL, err)
+		}
+		proxyConfig.HTTPProxy = cfg.ProxyURL
+		proxyConfig.HTTPSProxy = cfg.ProxyURL
+	}
+
+	if cfg.NoProxy != "" {
+		proxyConfig.NoProxy = cfg.NoProxy
+	}
+
+	fn := proxyConfig.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}, nil
+}
+
+// newTLSConfig builds the TLS configuration for the provided transport configuration. Certificates from CAFile and
+// CAPEM are trusted in addition to the system roots. When ServerFingerprints is set, the server is trusted if and only
+// if the SHA-256 fingerprint of its leaf certificate matches one of the fingerprints, which allows self-signed
+// certificates to be pinned without disabling verification. When CAFile or CAPEM is also set, the certificate chain
+// and the hostname are verified as well, so the leaf must both chain to a configured CA and be pinned.
+func newTLSConfig(cfg TransportConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.AllowUnverifiedSSL, // #nosec G402
+	}
+
+	if cfg.CAFile != "" || cfg.CAPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read ca_file %q: %w", cfg.CAFile, err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no valid PEM certificates found in ca_file %q", cfg.CAFile)
+			}
+		}
+
+		if cfg.CAPEM != "" {
+			if !pool.AppendCertsFromPEM([]byte(cfg.CAPEM)) {
+				return nil, errors.New("no valid PEM certificates found in ca_pem")
+			}
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(cfg.ServerFingerprints) > 0 {
+		fingerprints := map[string]bool{}
+		for _, f := range cfg.ServerFingerprints {
+			fingerprints[normalizeFingerprint(f)] = true
+		}
+
+		// The default verification is skipped so that self-signed certificates can be pinned. The chain is verified
+		// below when CAs are configured.
+		roots := tlsConfig.RootCAs
+		tlsConfig.InsecureSkipVerify = true // #nosec G402
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("server presented no certificate")
+			}
+
+			if roots != nil {
+				intermediates := x509.NewCertPool()
+				for _, cert := range state.PeerCertificates[1:] {
+					intermediates.AddCert(cert)
+				}
+
+				_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+					DNSName:       state.ServerName,
+					Roots:         roots,
+					Intermediates: intermediates,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to verify server certificate against ca_file and ca_pem: %w", err)
+				}
+			}
+
+			sum := sha256.Sum256(state.PeerCertificates[0].Raw)
+			fingerprint := hex.EncodeToString(sum[:])
+			if !fingerprints[fingerprint] {
+				return fmt.Errorf("server certificate fingerprint %s does not match any of tls_server_fingerprints", fingerprint)
+			}
+
+			return nil
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// normalizeFingerprint returns the lowercase hexadecimal form of a SHA-256 fingerprint, removing any colon or space
+// separators, so that "AB:CD:..." and "abcd..." are treated the same.
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fingerprint))
+}
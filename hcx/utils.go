@@ -6,34 +6,111 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // JobResult represents the result of a job execution, including its status, timing, and completion details.
 type JobResult struct {
-	JobID                   string `json:"jobId"`
-	Enterprise              string `json:"enterprise"`
-	Organization            string `json:"organization"`
-	Username                string `json:"username"`
-	IsQueued                bool   `json:"isQueued"`
-	IsCancelled             bool   `json:"isCancelled"`
-	IsRolledBack            bool   `json:"isRolledBack"`
-	CreateTimeEpoch         int64  `json:"createTimeEpoch"`
-	AbsoluteExpireTimeEpoch int64  `json:"absoluteExpireTimeEpoch"`
-	StartTime               int64  `json:"startTime"`
-	EndTime                 int64  `json:"endTime"`
-	PercentComplete         int    `json:"percentComplete"`
-	IsDone                  bool   `json:"isDone"`
-	DidFail                 bool   `json:"didFail"`
-	TimeToExecute           int64  `json:"timeToExecute"`
+	JobID                   string      `json:"jobId"`
+	JobType                 string      `json:"jobType"`
+	Workflow                string      `json:"workflow"`
+	State                   string      `json:"state"`
+	PreviousState           string      `json:"previousState"`
+	Enterprise              string      `json:"enterprise"`
+	Organization            string      `json:"organization"`
+	Username                string      `json:"username"`
+	IsQueued                bool        `json:"isQueued"`
+	IsCancelled             bool        `json:"isCancelled"`
+	IsRolledBack            bool        `json:"isRolledBack"`
+	CreateTimeEpoch         int64       `json:"createTimeEpoch"`
+	AbsoluteExpireTimeEpoch int64       `json:"absoluteExpireTimeEpoch"`
+	StartTime               int64       `json:"startTime"`
+	EndTime                 int64       `json:"endTime"`
+	PercentComplete         int         `json:"percentComplete"`
+	IsDone                  bool        `json:"isDone"`
+	DidFail                 bool        `json:"didFail"`
+	TimeToExecute           int64       `json:"timeToExecute"`
+	Errors                  []JobError  `json:"errors"`
+	SubJobs                 []JobResult `json:"subJobs"`
+}
+
+// JobError represents an error reported by an HCX job or task.
+type JobError struct {
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Text    string `json:"text"`
 }
 
 // TaskResult represents the result of a run.
 type TaskResult struct {
-	InterconnectTaskID string `json:"interconnectTaskId"`
-	Status             string `json:"status"`
+	InterconnectTaskID string       `json:"interconnectTaskId"`
+	Name               string       `json:"name"`
+	TaskType           string       `json:"taskType"`
+	Status             string       `json:"status"`
+	Progress           TaskProgress `json:"progressDetails"`
+	Errors             []JobError   `json:"errors"`
+	SubTasks           []TaskResult `json:"subTasks"`
+}
+
+// TaskProgress represents the progress details of an interconnect task.
+type TaskProgress struct {
+	Progress    int    `json:"progress"`
+	CurrentStep string `json:"currentStep"`
+	Message     string `json:"message"`
+}
+
+// Reason returns the most descriptive message of the error.
+func (e JobError) Reason() string {
+	return firstNonEmpty(e.Message, e.Text, e.Error, e.Code)
+}
+
+// Failure returns the step at which the job failed and the reason reported by HCX. The step is taken from the first
+// failed sub-job when there is one, and otherwise from the state of the job.
+func (jr JobResult) Failure() (step, reason string) {
+	for _, sub := range jr.SubJobs {
+		if sub.DidFail || sub.IsRolledBack || len(sub.Errors) > 0 {
+			step, reason = sub.Failure()
+			if reason == "" {
+				reason = joinReasons(jr.Errors)
+			}
+			return step, reason
+		}
+	}
+
+	return firstNonEmpty(jr.State, jr.PreviousState, jr.JobType), joinReasons(jr.Errors)
+}
+
+// Failure returns the step at which the task failed and the reason reported by HCX. The step is taken from the first
+// failed sub-task when there is one, and otherwise from the task itself.
+func (tr TaskResult) Failure() (step, reason string) {
+	for _, sub := range tr.SubTasks {
+		if isFailedStatus(sub.Status) || len(sub.Errors) > 0 {
+			step, reason = sub.Failure()
+			if reason == "" {
+				reason = joinReasons(tr.Errors)
+			}
+			return step, reason
+		}
+	}
+
+	return firstNonEmpty(tr.Progress.CurrentStep, tr.Name, tr.TaskType), firstNonEmpty(joinReasons(tr.Errors), tr.Progress.Message)
+}
+
+// joinReasons returns the reasons of the provided errors, separated by semicolons.
+func joinReasons(errs []JobError) string {
+	reasons := []string{}
+	for _, e := range errs {
+		if r := e.Reason(); r != "" {
+			reasons = append(reasons, r)
+		}
+	}
+
+	return strings.Join(reasons, "; ")
 }
 
 // ResourceContainerListFilterCloud defines a filter structure for categorizing resource containers as local or remote.
@@ -86,8 +163,8 @@ type PostNetworkBackingBody struct {
 }
 
 type PostNetworkBackingBodyFilter struct {
-	Cloud PostCloudListResultDataItem `json:"cloud"`
-	//VCenterInstanceUUID string   `json:"vCenterInstanceUuid"`
+	Cloud               PostCloudListResultDataItem `json:"cloud"`
+	VCenterInstanceUUID string                      `json:"vCenterInstanceUuid,omitempty"`
 	//ExcludeUsed         bool     `json:"excludeUsed"`
 	//BackingTypes        []string `json:"backingTypes"`
 }
@@ -104,9 +181,10 @@ type PostNetworkBackingResultData struct {
 
 // Dvpg represents a distributed port group with associated metadata.
 type Dvpg struct {
-	EntityID   string `json:"entity_id"`
-	Name       string `json:"name"`
-	EntityType string `json:"entityType"`
+	EntityID          string `json:"entity_id"`
+	Name              string `json:"name"`
+	EntityType        string `json:"entityType"`
+	VCenterInstanceID string `json:"vcenter_instanceId,omitempty"`
 }
 
 // GetVcInventoryResult represents the structure for the vCenter inventory result containing data about inventory items.
@@ -270,10 +348,10 @@ type GetApplianceResultItem struct {
 }
 
 // GetJobResult sends a request to retrieve the result of a job identified by the provided jobID, returning a JobResult object.
-func GetJobResult(c *Client, jobID string) (JobResult, error) {
+func GetJobResult(ctx context.Context, c *Client, jobID string) (JobResult, error) {
 	resp := JobResult{}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/hybridity/api/jobs/%s", c.HostURL, jobID), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/hybridity/api/jobs/%s", c.HostURL, jobID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -292,10 +370,10 @@ func GetJobResult(c *Client, jobID string) (JobResult, error) {
 }
 
 // GetTaskResult sends a request to retrieve the result of a task identified by the provided taskID.
-func GetTaskResult(c *Client, taskID string) (TaskResult, error) {
+func GetTaskResult(ctx context.Context, c *Client, taskID string) (TaskResult, error) {
 	resp := TaskResult{}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/hybridity/api/interconnect/tasks/%s", c.HostURL, taskID), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/hybridity/api/interconnect/tasks/%s", c.HostURL, taskID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -315,7 +393,7 @@ func GetTaskResult(c *Client, taskID string) (TaskResult, error) {
 
 // GetLocalContainer sends a request to retrieve the local resource container list and returns the first item as a
 // PostResourceContainerListResultDataItem.
-func GetLocalContainer(c *Client) (PostResourceContainerListResultDataItem, error) {
+func GetLocalContainer(ctx context.Context, c *Client) (PostResourceContainerListResultDataItem, error) {
 
 	body := PostResourceContainerListBody{
 		Filter: ResourceContainerListFilter{
@@ -334,12 +412,12 @@ func GetLocalContainer(c *Client) (PostResourceContainerListResultDataItem, erro
 
 	resp := PostResourceContainerListResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/service/inventory/resourcecontainer/list", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/service/inventory/resourcecontainer/list", c.HostURL), &buf)
 	if err != nil {
 		return PostResourceContainerListResultDataItem{}, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return PostResourceContainerListResultDataItem{}, fmt.Errorf("failed to send HTTP request: %w", err)
 	}
@@ -354,7 +432,7 @@ func GetLocalContainer(c *Client) (PostResourceContainerListResultDataItem, erro
 
 // GetRemoteContainer sends a request to retrieve the remote resource container list and returns the first item as a
 // PostResourceContainerListResultDataItem.
-func GetRemoteContainer(c *Client) (PostResourceContainerListResultDataItem, error) {
+func GetRemoteContainer(ctx context.Context, c *Client) (PostResourceContainerListResultDataItem, error) {
 
 	body := PostResourceContainerListBody{
 		Filter: ResourceContainerListFilter{
@@ -373,12 +451,12 @@ func GetRemoteContainer(c *Client) (PostResourceContainerListResultDataItem, err
 
 	resp := PostResourceContainerListResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/service/inventory/resourcecontainer/list", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/service/inventory/resourcecontainer/list", c.HostURL), &buf)
 	if err != nil {
 		return PostResourceContainerListResultDataItem{}, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return PostResourceContainerListResultDataItem{}, fmt.Errorf("failed to send HTTP request: %w", err)
 	}
@@ -392,13 +470,21 @@ func GetRemoteContainer(c *Client) (PostResourceContainerListResultDataItem, err
 }
 
 // GetNetworkBacking sends a request to retrieve a network's backing information.
-func GetNetworkBacking(c *Client, endpointID, network, networkType string) (Dvpg, error) {
+func GetNetworkBacking(ctx context.Context, c *Client, endpointID, network, networkType string) (Dvpg, error) {
+	return GetVcNetworkBacking(ctx, c, endpointID, "", network, networkType)
+}
+
+// GetVcNetworkBacking sends a request to retrieve the backing information of a network of the vCenter instance
+// identified by vCenterInstanceUUID. All the vCenter instances of the endpoint are searched when vCenterInstanceUUID
+// is empty.
+func GetVcNetworkBacking(ctx context.Context, c *Client, endpointID, vCenterInstanceUUID, network, networkType string) (Dvpg, error) {
 
 	body := PostNetworkBackingBody{
 		Filter: PostNetworkBackingBodyFilter{
 			Cloud: PostCloudListResultDataItem{
 				EndpointID: endpointID,
 			},
+			VCenterInstanceUUID: vCenterInstanceUUID,
 		},
 	}
 
@@ -410,12 +496,12 @@ func GetNetworkBacking(c *Client, endpointID, network, networkType string) (Dvpg
 
 	resp := PostNetworkBackingResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/service/inventory/networks", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/service/inventory/networks", c.HostURL), &buf)
 	if err != nil {
 		return Dvpg{}, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return Dvpg{}, fmt.Errorf("failed to send POST request: %w", err)
 	}
@@ -426,28 +512,37 @@ func GetNetworkBacking(c *Client, endpointID, network, networkType string) (Dvpg
 	}
 
 	for _, j := range resp.Data.Items {
+		// Skip the networks of other vCenter instances, in case the filter is not applied.
+		if vCenterInstanceUUID != "" && j.VCenterInstanceID != "" && j.VCenterInstanceID != vCenterInstanceUUID {
+			continue
+		}
 		if j.Name == network && j.EntityType == networkType {
 			return j, nil
 		}
 	}
 
+	if vCenterInstanceUUID != "" {
+		return Dvpg{}, fmt.Errorf("failed to find network %s of type %s in vCenter instance %s", network, networkType,
+			vCenterInstanceUUID)
+	}
+
 	return Dvpg{}, fmt.Errorf("failed to find matching network info in GetNetworkBacking")
 }
 
 // GetVcInventory sends a request to retrieve the vCenter resource inventory.
-func GetVcInventory(c *Client) (GetVcInventoryResultDataItem, error) {
+func GetVcInventory(ctx context.Context, c *Client) (GetVcInventoryResultDataItem, error) {
 
 	var jsonBody = []byte("{}")
 	buf := bytes.NewBuffer(jsonBody)
 
 	resp := GetVcInventoryResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/service/inventory/vc/list", c.HostURL), buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/service/inventory/vc/list", c.HostURL), buf)
 	if err != nil {
 		return GetVcInventoryResultDataItem{}, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return GetVcInventoryResultDataItem{}, fmt.Errorf("failed to send POST request: %w", err)
 	}
@@ -461,7 +556,7 @@ func GetVcInventory(c *Client) (GetVcInventoryResultDataItem, error) {
 }
 
 // GetVcDatastore sends a request to query a vCenter datastore.
-func GetVcDatastore(c *Client, datastoreName, vcuuid, cluster string) (GetVcDatastoreResultDataItem, error) {
+func GetVcDatastore(ctx context.Context, c *Client, datastoreName, vcuuid, cluster string) (GetVcDatastoreResultDataItem, error) {
 
 	body := GetVcDatastoreBody{
 		Filter: GetVcDatastoreFilter{
@@ -479,12 +574,12 @@ func GetVcDatastore(c *Client, datastoreName, vcuuid, cluster string) (GetVcData
 
 	resp := GetVcDatastoreResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/service/inventory/vc/datastores/query", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/service/inventory/vc/datastores/query", c.HostURL), &buf)
 	if err != nil {
 		return GetVcDatastoreResultDataItem{}, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return GetVcDatastoreResultDataItem{}, fmt.Errorf("failed to send POST request: %w", err)
 	}
@@ -504,7 +599,7 @@ func GetVcDatastore(c *Client, datastoreName, vcuuid, cluster string) (GetVcData
 }
 
 // GetVcDvs sends a request to query a distributed switch.
-func GetVcDvs(c *Client, dvsName, vcuuid, cluster string) (GetVcDvsResultDataItem, error) {
+func GetVcDvs(ctx context.Context, c *Client, dvsName, vcuuid, cluster string) (GetVcDvsResultDataItem, error) {
 
 	body := GetVcDvsBody{
 		Filter: GetVcDvsFilter{
@@ -522,12 +617,12 @@ func GetVcDvs(c *Client, dvsName, vcuuid, cluster string) (GetVcDvsResultDataIte
 
 	resp := GetVcDvsResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/service/inventory/vc/dvs/query", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/service/inventory/vc/dvs/query", c.HostURL), &buf)
 	if err != nil {
 		return GetVcDvsResultDataItem{}, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return GetVcDvsResultDataItem{}, fmt.Errorf("failed to send POST request: %w", err)
 	}
@@ -547,7 +642,7 @@ func GetVcDvs(c *Client, dvsName, vcuuid, cluster string) (GetVcDvsResultDataIte
 }
 
 // GetRemoteCloudList sends a request to retrieve a list of remote clouds and returns the resulting PostCloudListResult.
-func GetRemoteCloudList(c *Client) (PostCloudListResult, error) {
+func GetRemoteCloudList(ctx context.Context, c *Client) (PostCloudListResult, error) {
 
 	body := PostCloudListBody{
 		Filter: PostCloudListFilter{
@@ -563,12 +658,12 @@ func GetRemoteCloudList(c *Client) (PostCloudListResult, error) {
 
 	resp := PostCloudListResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/service/inventory/cloud/list", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/service/inventory/cloud/list", c.HostURL), &buf)
 	if err != nil {
 		return PostCloudListResult{}, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return PostCloudListResult{}, fmt.Errorf("failed to send POST request: %w", err)
 	}
@@ -582,7 +677,7 @@ func GetRemoteCloudList(c *Client) (PostCloudListResult, error) {
 }
 
 // GetLocalCloudList sends a request to retrieve a list of local clouds and returns the resulting PostCloudListResult.
-func GetLocalCloudList(c *Client) (PostCloudListResult, error) {
+func GetLocalCloudList(ctx context.Context, c *Client) (PostCloudListResult, error) {
 
 	body := PostCloudListBody{
 		Filter: PostCloudListFilter{
@@ -598,12 +693,12 @@ func GetLocalCloudList(c *Client) (PostCloudListResult, error) {
 
 	resp := PostCloudListResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/service/inventory/cloud/list", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/service/inventory/cloud/list", c.HostURL), &buf)
 	if err != nil {
 		return PostCloudListResult{}, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return PostCloudListResult{}, fmt.Errorf("failed to send POST request: %w", err)
 	}
@@ -618,7 +713,7 @@ func GetLocalCloudList(c *Client) (PostCloudListResult, error) {
 
 // GetAppliance sends a request to query appliances based on the given endpointID and serviceMeshID.
 // It returns a matching GetApplianceResultItem (with a network extension count less than 9) or an error.
-func GetAppliance(c *Client, endpointID string, serviceMeshID string) (GetApplianceResultItem, error) {
+func GetAppliance(ctx context.Context, c *Client, endpointID string, serviceMeshID string) (GetApplianceResultItem, error) {
 
 	body := GetApplianceBody{
 		Filter: GetApplianceBodyFilter{
@@ -635,12 +730,12 @@ func GetAppliance(c *Client, endpointID string, serviceMeshID string) (GetApplia
 
 	resp := GetApplianceResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/interconnect/appliances/query", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/interconnect/appliances/query", c.HostURL), &buf)
 	if err != nil {
 		return GetApplianceResultItem{}, fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return GetApplianceResultItem{}, fmt.Errorf("failed to send POST request: %w", err)
 	}
@@ -661,7 +756,7 @@ func GetAppliance(c *Client, endpointID string, serviceMeshID string) (GetApplia
 
 // GetAppliances sends a request to retrieve all appliances matching the given endpointID and serviceMeshID.
 // Returns a slice of GetApplianceResultItem objects or an error if the request fails.
-func GetAppliances(c *Client, endpointID string, serviceMeshID string) ([]GetApplianceResultItem, error) {
+func GetAppliances(ctx context.Context, c *Client, endpointID string, serviceMeshID string) ([]GetApplianceResultItem, error) {
 
 	body := GetApplianceBody{
 		Filter: GetApplianceBodyFilter{
@@ -679,12 +774,12 @@ func GetAppliances(c *Client, endpointID string, serviceMeshID string) ([]GetApp
 
 	resp := GetApplianceResult{}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/hybridity/api/interconnect/appliances/query", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/hybridity/api/interconnect/appliances/query", c.HostURL), &buf)
 	if err != nil {
 		return []GetApplianceResultItem{}, fmt.Errorf("failed to POST create requests: %w", err)
 	}
 
-	_, r, err := c.doRequest(req)
+	_, r, err := c.doRequest(withIdempotent(req))
 	if err != nil {
 		return []GetApplianceResultItem{}, fmt.Errorf("failed to send POST requests: %w", err)
 	}
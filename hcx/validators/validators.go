@@ -5,7 +5,11 @@
 package validators
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/netip"
+	"strings"
 
 	"github.com/vmware/terraform-provider-hcx/hcx/constants"
 )
@@ -28,3 +32,135 @@ func ValidateNetworkType(val interface{}, key string) (warns []string, errs []er
 	errs = append(errs, fmt.Errorf("%q must be one of %v, got: %s", key, constants.AllowedNetworkTypes, networkType))
 	return warns, errs
 }
+
+// ValidateSHA256Fingerprint validates that the provided value is a string containing a SHA-256 fingerprint written as
+// 64 hexadecimal characters, optionally separated by colons or spaces.
+// Returns warnings and errors based on value validation.
+func ValidateSHA256Fingerprint(val interface{}, key string) (warns []string, errs []error) {
+	fingerprint, ok := val.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("%q must be a string, got: %T", key, val))
+		return warns, errs
+	}
+
+	normalized := strings.NewReplacer(":", "", " ", "").Replace(fingerprint)
+	if _, err := hex.DecodeString(normalized); err != nil || len(normalized) != 64 {
+		errs = append(errs, fmt.Errorf("%q must be a SHA-256 fingerprint of 64 hexadecimal characters, got: %s", key, fingerprint))
+	}
+
+	return warns, errs
+}
+
+// ValidateIPAddress validates that the provided value is a string containing an IPv4 or IPv6 address. An empty string
+// is accepted for optional attributes.
+// Returns warnings and errors based on value validation.
+func ValidateIPAddress(val interface{}, key string) (warns []string, errs []error) {
+	address, ok := val.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("%q must be a string, got: %T", key, val))
+		return warns, errs
+	}
+
+	if address == "" {
+		return warns, errs
+	}
+
+	if _, err := netip.ParseAddr(address); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be an IPv4 or IPv6 address, got: %s", key, address))
+	}
+
+	return warns, errs
+}
+
+// IPRange represents a range of IP addresses of a network profile, with the attribute path of the range used in
+// validation errors.
+type IPRange struct {
+	Path         string
+	StartAddress string
+	EndAddress   string
+}
+
+// ValidateIPRanges validates that the IP ranges of a network profile are well-formed and do not overlap. When gateway
+// is set, the ranges must also lie inside the subnet defined by gateway and prefixLength, and must not include the
+// gateway. The prefix length must be valid for the IP version of the gateway or, without a gateway, of the first IP
+// range; a prefix length of 0 is only accepted without a gateway when allowNoPrefix is set. Addresses that are not
+// valid IP addresses are skipped, since they are reported by ValidateIPAddress. Returns an error naming the attribute
+// path of each invalid value, where the gateway and the prefix length are named after path, the path of their IP
+// scope.
+func ValidateIPRanges(path, gateway string, prefixLength int, ranges []IPRange, allowNoPrefix bool) error {
+	var errs []error
+
+	var subnet netip.Prefix
+	gw, err := netip.ParseAddr(gateway)
+	if err == nil {
+		subnet, err = gw.Prefix(prefixLength)
+		if err != nil || prefixLength <= 0 {
+			errs = append(errs, fmt.Errorf("%q must be between 1 and %d for gateway %s, got: %d", path+"prefix_length",
+				gw.BitLen(), gateway, prefixLength))
+			subnet = netip.Prefix{}
+		}
+	} else if prefixLength != 0 || !allowNoPrefix {
+		for _, r := range ranges {
+			start, err := netip.ParseAddr(r.StartAddress)
+			if err != nil {
+				continue
+			}
+			if prefixLength <= 0 || prefixLength > start.BitLen() {
+				errs = append(errs, fmt.Errorf("%q must be between 1 and %d for the IP version of %q, got: %d",
+					path+"prefix_length", start.BitLen(), r.Path, prefixLength))
+			}
+			break
+		}
+	}
+
+	type parsedRange struct {
+		path       string
+		start, end netip.Addr
+	}
+	parsed := []parsedRange{}
+
+	for _, r := range ranges {
+		start, startErr := netip.ParseAddr(r.StartAddress)
+		end, endErr := netip.ParseAddr(r.EndAddress)
+		if startErr != nil || endErr != nil {
+			continue
+		}
+
+		switch {
+		case start.Is4() != end.Is4():
+			errs = append(errs, fmt.Errorf("%q must be of the same IP version as %q", r.Path+".end_address", r.Path+".start_address"))
+			continue
+		case start.Compare(end) > 0:
+			errs = append(errs, fmt.Errorf("%q must not be greater than %q, got: %s > %s", r.Path+".start_address",
+				r.Path+".end_address", start, end))
+			continue
+		}
+
+		if subnet.IsValid() {
+			inSubnet := true
+			if !subnet.Contains(start) {
+				errs = append(errs, fmt.Errorf("%q must be inside the subnet %s of the gateway, got: %s",
+					r.Path+".start_address", subnet, start))
+				inSubnet = false
+			}
+			if !subnet.Contains(end) {
+				errs = append(errs, fmt.Errorf("%q must be inside the subnet %s of the gateway, got: %s",
+					r.Path+".end_address", subnet, end))
+				inSubnet = false
+			}
+			if inSubnet && start.Compare(gw) <= 0 && gw.Compare(end) <= 0 {
+				errs = append(errs, fmt.Errorf("%q must not be inside the IP range %s-%s of %q", path+"gateway", start, end, r.Path))
+			}
+		}
+
+		for _, p := range parsed {
+			if p.start.Is4() == start.Is4() && p.start.Compare(end) <= 0 && start.Compare(p.end) <= 0 {
+				errs = append(errs, fmt.Errorf("%q must not overlap %q, got: %s-%s and %s-%s", r.Path, p.path, start, end,
+					p.start, p.end))
+			}
+		}
+		parsed = append(parsed, parsedRange{path: r.Path, start: start, end: end})
+	}
+
+	return errors.Join(errs...)
+}
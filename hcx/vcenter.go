@@ -6,6 +6,7 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -40,6 +41,11 @@ type InsertvCenterResult struct {
 	InsertvCenterData InsertvCenterData `json:"data"`
 }
 
+// GetvCenterResult represents the vCenter instance configurations returned by the HCX Manager.
+type GetvCenterResult struct {
+	InsertvCenterData InsertvCenterData `json:"data"`
+}
+
 // DeletevCenterResult represents the result returned after a vCenter instance is deleted, containing related data.
 type DeletevCenterResult struct {
 	InsertvCenterData InsertvCenterData `json:"data"`
@@ -47,7 +53,7 @@ type DeletevCenterResult struct {
 
 // InsertvCenter sends a request to add a new vCenter instance configuration using the provided body and returns the
 // resulting InsertvCenterResult object. Returns an error if the request fails or the response cannot be parsed.
-func InsertvCenter(c *Client, body InsertvCenterBody) (InsertvCenterResult, error) {
+func InsertvCenter(ctx context.Context, c *Client, body InsertvCenterBody) (InsertvCenterResult, error) {
 	resp := InsertvCenterResult{}
 
 	var buf bytes.Buffer
@@ -56,7 +62,7 @@ func InsertvCenter(c *Client, body InsertvCenterBody) (InsertvCenterResult, erro
 		return resp, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s:9443/api/admin/global/config/vcenter", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s:9443/api/admin/global/config/vcenter", c.HostURL), &buf)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -74,13 +80,44 @@ func InsertvCenter(c *Client, body InsertvCenterBody) (InsertvCenterResult, erro
 	return resp, nil
 }
 
+// UpdatevCenter sends a request to update the vCenter instance configuration identified by the provided vCenterUUID
+// using the provided body and returns the resulting InsertvCenterResult object. Returns an error if the request fails
+// or the response cannot be parsed.
+func UpdatevCenter(ctx context.Context, c *Client, vCenterUUID string, body InsertvCenterBody) (InsertvCenterResult, error) {
+
+	resp := InsertvCenterResult{}
+
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s:9443/api/admin/global/config/vcenter/%s", c.HostURL, vCenterUUID), &buf)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create PUT request: %w", err)
+	}
+
+	_, r, err := c.doAdminRequest(withIdempotent(req))
+	if err != nil {
+		return resp, fmt.Errorf("failed to send PUT request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	return resp, nil
+}
+
 // DeletevCenter sends a request to remove a vCenter instance configuration identified by the provided vCenterUUID and
 // returns the resulting DeletevCenterResult object. Returns an error if the request fails or the response cannot be
 // parsed.
-func DeletevCenter(c *Client, vCenterUUID string) (DeletevCenterResult, error) {
+func DeletevCenter(ctx context.Context, c *Client, vCenterUUID string) (DeletevCenterResult, error) {
 	resp := DeletevCenterResult{}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s:9443/api/admin/global/config/vcenter/%s", c.HostURL, vCenterUUID), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s:9443/api/admin/global/config/vcenter/%s", c.HostURL, vCenterUUID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create DELETE request: %w", err)
 	}
@@ -97,3 +134,27 @@ func DeletevCenter(c *Client, vCenterUUID string) (DeletevCenterResult, error) {
 
 	return resp, nil
 }
+
+// GetvCenter sends a GET request to retrieve the vCenter instance configurations and returns the resulting
+// GetvCenterResult object. Returns an error if the request fails or the response cannot be parsed.
+func GetvCenter(ctx context.Context, c *Client) (GetvCenterResult, error) {
+
+	resp := GetvCenterResult{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s:9443/api/admin/global/config/vcenter", c.HostURL), nil)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create GET request: %w", err)
+	}
+
+	_, r, err := c.doAdminRequest(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send GET request: %w", err)
+	}
+
+	err = json.Unmarshal(r, &resp)
+	if err != nil {
+		return resp, fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	return resp, nil
+}
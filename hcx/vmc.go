@@ -6,11 +6,13 @@ package hcx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"time"
+	"net/url"
+	"strings"
 
 	"github.com/vmware/terraform-provider-hcx/hcx/constants"
 )
@@ -61,17 +63,17 @@ type DeactivateHcxOnSDDCResults struct {
 
 // VmcAuthenticate sends a request to authenticate with the VMware Cloud (VMC) API using the provided token.
 // Returns an access token as a string or an error if the request fails or the response cannot be parsed.
-func VmcAuthenticate(token string) (string, error) {
+func VmcAuthenticate(ctx context.Context, client *Client, token string) (string, error) {
 
-	c := Client{
-		HTTPClient: &http.Client{Timeout: 60 * time.Second},
-		HostURL:    constants.VmcAuthURL,
-	}
+	c := client.cloudClient(constants.VmcAuthURL)
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/auth/api-tokens/authorize?refresh_token=%s", c.HostURL, token), nil)
+	// The token is sent in the form body rather than the query string, so that it does not appear in logged URLs.
+	form := url.Values{"refresh_token": {token}}
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/auth/api-tokens/authorize", c.HostURL), strings.NewReader(form.Encode()))
 	if err != nil {
 		return "", fmt.Errorf("failed to create POST request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	_, r, err := c.doVmcRequest(req)
 	if err != nil {
@@ -89,12 +91,9 @@ func VmcAuthenticate(token string) (string, error) {
 
 // CloudAuthenticate sends a request to authenticate to the HCX cloud service using the provided token.
 // On success, it sets the HcxToken field of the provided Client.
-func CloudAuthenticate(client *Client, token string) error {
+func CloudAuthenticate(ctx context.Context, client *Client, token string) error {
 
-	c := Client{
-		HTTPClient: &http.Client{Timeout: 60 * time.Second},
-		HostURL:    constants.HcxCloudAuthURL,
-	}
+	c := client.cloudClient(constants.HcxCloudAuthURL)
 
 	body := CloudAuthorizationBody{
 		Token: token,
@@ -106,12 +105,12 @@ func CloudAuthenticate(client *Client, token string) error {
 		return fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/sessions", c.HostURL), &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/sessions", c.HostURL), &buf)
 	if err != nil {
 		return fmt.Errorf("failed to create POST request: %w", err)
 	}
 
-	resp, _, err := c.doVmcRequest(req)
+	resp, _, err := c.doVmcRequest(withIdempotent(req))
 	if err != nil {
 		return fmt.Errorf("failed to send POST request: %w", err)
 	}
@@ -127,15 +126,12 @@ func CloudAuthenticate(client *Client, token string) error {
 
 // GetSddcByName sends a request to retrieve an SDDC by name.
 // Returns the matching SDDC object or an error.
-func GetSddcByName(client *Client, sddcName string) (SDDC, error) {
+func GetSddcByName(ctx context.Context, client *Client, sddcName string) (SDDC, error) {
 
-	c := Client{
-		HTTPClient: &http.Client{Timeout: 60 * time.Second},
-		HostURL:    constants.HcxCloudConsumerURL,
-		HcxToken:   client.HcxToken,
-	}
+	c := client.cloudClient(constants.HcxCloudConsumerURL)
+	c.HcxToken = client.HcxToken
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/sddcs", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/sddcs", c.HostURL), nil)
 	if err != nil {
 		return SDDC{}, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -161,15 +157,12 @@ func GetSddcByName(client *Client, sddcName string) (SDDC, error) {
 }
 
 // GetSddcByID sends a request to retrieve an SDDC by ID.
-func GetSddcByID(client *Client, sddcID string) (SDDC, error) {
+func GetSddcByID(ctx context.Context, client *Client, sddcID string) (SDDC, error) {
 
-	c := Client{
-		HTTPClient: &http.Client{Timeout: 60 * time.Second},
-		HostURL:    constants.HcxCloudConsumerURL,
-		HcxToken:   client.HcxToken,
-	}
+	c := client.cloudClient(constants.HcxCloudConsumerURL)
+	c.HcxToken = client.HcxToken
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/sddcs", c.HostURL), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/sddcs", c.HostURL), nil)
 	if err != nil {
 		return SDDC{}, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -195,17 +188,14 @@ func GetSddcByID(client *Client, sddcID string) (SDDC, error) {
 }
 
 // ActivateHcxOnSDDC sends a request to activate HCX on the specified SDDC.
-func ActivateHcxOnSDDC(client *Client, sddcID string) (ActivateHcxOnSDDCResults, error) {
+func ActivateHcxOnSDDC(ctx context.Context, client *Client, sddcID string) (ActivateHcxOnSDDCResults, error) {
 
 	resp := ActivateHcxOnSDDCResults{}
 
-	c := Client{
-		HTTPClient: &http.Client{Timeout: 60 * time.Second},
-		HostURL:    constants.HcxCloudConsumerURL,
-		HcxToken:   client.HcxToken,
-	}
+	c := client.cloudClient(constants.HcxCloudConsumerURL)
+	c.HcxToken = client.HcxToken
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/sddcs/%s?action=activate", c.HostURL, sddcID), nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/sddcs/%s?action=activate", c.HostURL, sddcID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -224,17 +214,14 @@ func ActivateHcxOnSDDC(client *Client, sddcID string) (ActivateHcxOnSDDCResults,
 }
 
 // DeactivateHcxOnSDDC sends a request to deactivate HCX on the specified SDDC.
-func DeactivateHcxOnSDDC(client *Client, sddcID string) (DeactivateHcxOnSDDCResults, error) {
+func DeactivateHcxOnSDDC(ctx context.Context, client *Client, sddcID string) (DeactivateHcxOnSDDCResults, error) {
 
 	resp := DeactivateHcxOnSDDCResults{}
 
-	c := Client{
-		HTTPClient: &http.Client{Timeout: 60 * time.Second},
-		HostURL:    constants.HcxCloudConsumerURL,
-		HcxToken:   client.HcxToken,
-	}
+	c := client.cloudClient(constants.HcxCloudConsumerURL)
+	c.HcxToken = client.HcxToken
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/sddcs/%s?action=deactivate", c.HostURL, sddcID), nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/sddcs/%s?action=deactivate", c.HostURL, sddcID), nil)
 	if err != nil {
 		return resp, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -0,0 +1,339 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vmware/terraform-provider-hcx/hcx/constants"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Default polling settings, used when WaitOptions leaves them unset.
+const (
+	DefaultPollInterval     = 5 * time.Second
+	DefaultMaxPollInterval  = 30 * time.Second
+	DefaultProgressInterval = time.Minute
+
+	// DefaultDetachedTimeout bounds the requests sent after a wait was interrupted, such as the lookup of the object
+	// created by the HCX job or task.
+	DefaultDetachedTimeout = 2 * time.Minute
+)
+
+// WaitOptions defines how an asynchronous HCX operation is polled. PollInterval is the initial wait between polls,
+// which grows by half after each poll up to MaxPollInterval. Timeout bounds the whole wait; when zero, only the
+// deadline of the context applies. ProgressInterval is the minimum time between two progress log entries.
+type WaitOptions struct {
+	PollInterval     time.Duration
+	MaxPollInterval  time.Duration
+	Timeout          time.Duration
+	ProgressInterval time.Duration
+}
+
+// WaitError represents an asynchronous HCX operation that did not complete successfully, because it failed, was
+// cancelled or rolled back, or because waiting for it timed out or was interrupted. For failed operations, Step and
+// Reason hold the failing step and the reason reported by HCX, when available.
+type WaitError struct {
+	Kind    string
+	ID      string
+	State   string
+	Step    string
+	Reason  string
+	Elapsed time.Duration
+	Err     error
+}
+
+// Error returns a readable description of the failed wait.
+func (e *WaitError) Error() string {
+	name := e.Kind
+	if e.ID != "" {
+		name = fmt.Sprintf("%s %s", e.Kind, e.ID)
+	}
+
+	var msg string
+	switch e.State {
+	case constants.TimedOutStatus:
+		msg = fmt.Sprintf("timed out after %s waiting for HCX %s; the operation may still be running, increase the "+
+			"timeouts of the resource if it needs more time", e.Elapsed.Round(time.Second), name)
+	case constants.InterruptedStatus:
+		msg = fmt.Sprintf("interrupted after %s while waiting for HCX %s", e.Elapsed.Round(time.Second), name)
+	case constants.CancelledStatus:
+		msg = fmt.Sprintf("HCX %s was cancelled", name)
+	case constants.RolledBackStatus:
+		msg = fmt.Sprintf("HCX %s failed and was rolled back", name)
+	default:
+		msg = fmt.Sprintf("HCX %s failed", name)
+	}
+
+	if e.Step != "" {
+		msg = fmt.Sprintf("%s at step %q", msg, e.Step)
+	}
+
+	if e.Reason != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Reason)
+	}
+
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %s", msg, e.Err)
+	}
+
+	if e.State == constants.InterruptedStatus {
+		msg = fmt.Sprintf("%s; the %s may still be running in HCX", msg, e.Kind)
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying error, such as context.DeadlineExceeded for a timed out wait.
+func (e *WaitError) Unwrap() error {
+	return e.Err
+}
+
+// WaitForJob polls the HCX job identified by jobID until it is done. Returns the final JobResult, or a *WaitError if
+// the job failed, was cancelled or rolled back, or if the wait timed out or was interrupted.
+func WaitForJob(ctx context.Context, c *Client, jobID string, opts WaitOptions) (JobResult, error) {
+	var jr JobResult
+	progress := newProgressLogger("job", jobID, opts)
+
+	err := waitUntil(ctx, opts, "job", jobID, func(ctx context.Context) (bool, error) {
+		var err error
+		jr, err = GetJobResult(ctx, c, jobID)
+		if err != nil {
+			return false, err
+		}
+
+		var started time.Time
+		if jr.StartTime > 0 {
+			started = time.UnixMilli(jr.StartTime)
+		}
+		progress.log(ctx, jr.PercentComplete, firstNonEmpty(jr.State, jr.JobType), started)
+
+		return jobStatus(jobID, jr)
+	})
+
+	return jr, err
+}
+
+// WaitForTask polls the HCX interconnect task identified by taskID until it succeeds. Returns the final TaskResult,
+// or a *WaitError if the task failed or was cancelled, or if the wait timed out or was interrupted.
+func WaitForTask(ctx context.Context, c *Client, taskID string, opts WaitOptions) (TaskResult, error) {
+	var tr TaskResult
+	progress := newProgressLogger("task", taskID, opts)
+
+	err := waitUntil(ctx, opts, "task", taskID, func(ctx context.Context) (bool, error) {
+		var err error
+		tr, err = GetTaskResult(ctx, c, taskID)
+		if err != nil {
+			return false, err
+		}
+
+		progress.log(ctx, tr.Progress.Progress, firstNonEmpty(tr.Progress.CurrentStep, tr.Progress.Message), time.Time{})
+
+		return taskStatus(taskID, tr)
+	})
+
+	return tr, err
+}
+
+// CheckJob retrieves the HCX job identified by jobID once. Returns true if the job is done, false if it is still
+// running, or a *WaitError if it failed, was cancelled or rolled back.
+func CheckJob(ctx context.Context, c *Client, jobID string) (bool, error) {
+	jr, err := GetJobResult(ctx, c, jobID)
+	if err != nil {
+		return false, err
+	}
+
+	return jobStatus(jobID, jr)
+}
+
+// CheckTask retrieves the HCX interconnect task identified by taskID once. Returns true if the task succeeded, false
+// if it is still running, or a *WaitError if it failed or was cancelled.
+func CheckTask(ctx context.Context, c *Client, taskID string) (bool, error) {
+	tr, err := GetTaskResult(ctx, c, taskID)
+	if err != nil {
+		return false, err
+	}
+
+	return taskStatus(taskID, tr)
+}
+
+// jobStatus reports whether the job is done, or returns a *WaitError if it failed, was cancelled or rolled back.
+func jobStatus(jobID string, jr JobResult) (bool, error) {
+	state := ""
+	switch {
+	case jr.IsCancelled:
+		state = constants.CancelledStatus
+	case jr.IsRolledBack:
+		state = constants.RolledBackStatus
+	case jr.DidFail:
+		state = constants.FailedStatus
+	}
+
+	if state != "" {
+		step, reason := jr.Failure()
+		return false, &WaitError{Kind: "job", ID: jobID, State: state, Step: step, Reason: reason}
+	}
+
+	return jr.IsDone, nil
+}
+
+// taskStatus reports whether the task succeeded, or returns a *WaitError if it failed or was cancelled.
+func taskStatus(taskID string, tr TaskResult) (bool, error) {
+	if strings.EqualFold(tr.Status, constants.SuccessStatus) {
+		return true, nil
+	}
+
+	if isFailedStatus(tr.Status) {
+		state := constants.FailedStatus
+		if !strings.EqualFold(tr.Status, constants.FailedStatus) {
+			state = constants.CancelledStatus
+		}
+
+		step, reason := tr.Failure()
+		return false, &WaitError{Kind: "task", ID: taskID, State: state, Step: step, Reason: reason}
+	}
+
+	return false, nil
+}
+
+// interrupted reports whether err is a *WaitError for an interrupted wait.
+func interrupted(err error) bool {
+	var waitErr *WaitError
+	return errors.As(err, &waitErr) && waitErr.State == constants.InterruptedStatus
+}
+
+// detachedContext returns a context that outlives the cancellation of ctx, bounded by DefaultDetachedTimeout. It is
+// used for the requests that keep the state consistent after an interruption.
+func detachedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), DefaultDetachedTimeout)
+}
+
+// progressLogger writes throttled progress log entries while waiting for an HCX job or task.
+type progressLogger struct {
+	kind     string
+	id       string
+	interval time.Duration
+	start    time.Time
+	last     time.Time
+	step     string
+}
+
+// newProgressLogger returns a progressLogger for the operation identified by kind and id.
+func newProgressLogger(kind, id string, opts WaitOptions) *progressLogger {
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+
+	return &progressLogger{kind: kind, id: id, interval: interval, start: time.Now()}
+}
+
+// log writes a progress entry when the current step changed or when the progress interval elapsed since the last
+// entry. The elapsed time is measured from started when set, and otherwise from the start of the wait.
+func (p *progressLogger) log(ctx context.Context, percent int, step string, started time.Time) {
+	now := time.Now()
+	if !p.last.IsZero() && step == p.step && now.Sub(p.last) < p.interval {
+		return
+	}
+	p.last = now
+	p.step = step
+
+	if started.IsZero() {
+		started = p.start
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Waiting for HCX %s", p.kind), map[string]interface{}{
+		p.kind + "_id":     p.id,
+		"percent_complete": percent,
+		"current_step":     step,
+		"elapsed":          now.Sub(started).Round(time.Second).String(),
+	})
+}
+
+// isFailedStatus reports whether an interconnect task status means the task failed or was cancelled.
+func isFailedStatus(status string) bool {
+	switch strings.ToUpper(status) {
+	case constants.FailedStatus, constants.CancelledStatus, "CANCELED":
+		return true
+	}
+
+	return false
+}
+
+// waitUntil calls check until it reports completion or returns an error, waiting between calls according to opts.
+// The kind and id describe the awaited operation in the *WaitError returned when the wait times out or is
+// interrupted.
+func waitUntil(ctx context.Context, opts WaitOptions, kind, id string, check func(ctx context.Context) (bool, error)) error {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	maxInterval := opts.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultMaxPollInterval
+	}
+	maxInterval = max(maxInterval, interval)
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	stopped := func(err error) error {
+		state := constants.InterruptedStatus
+		if errors.Is(err, context.DeadlineExceeded) {
+			state = constants.TimedOutStatus
+		}
+
+		return &WaitError{Kind: kind, ID: id, State: state, Elapsed: time.Since(start), Err: err}
+	}
+
+	for {
+		done, err := check(ctx)
+		if err == nil && done {
+			return nil
+		}
+
+		// A request failing because the wait was stopped is reported as a timeout or interruption.
+		if ctx.Err() != nil {
+			return stopped(ctx.Err())
+		}
+
+		if err != nil {
+			var waitErr *WaitError
+			if errors.As(err, &waitErr) {
+				waitErr.Elapsed = time.Since(start)
+			}
+			return err
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return stopped(err)
+		}
+
+		interval = min(interval+interval/2, maxInterval)
+	}
+}
+
+// WaitForAppEngine polls the HCX application engine until it reports the provided status, such as
+// constants.StoppedStatus or constants.RunningStatus.
+func WaitForAppEngine(ctx context.Context, c *Client, status string, opts WaitOptions) error {
+	return waitUntil(ctx, opts, "application engine", status, func(ctx context.Context) (bool, error) {
+		res, err := GetAppEngineStatus(ctx, c)
+		if err != nil {
+			return false, err
+		}
+
+		return res.Result == status, nil
+	})
+}